@@ -3,19 +3,23 @@
 package ecbratex
 
 import (
+	"context"
 	"errors"
+	"github.com/jieggii/ecbratex/pkg/decoder"
 	"github.com/jieggii/ecbratex/pkg/provider"
 	"github.com/jieggii/ecbratex/pkg/record"
 	"github.com/jieggii/ecbratex/pkg/timeseries"
+	"github.com/jieggii/ecbratex/pkg/validate"
 	"github.com/jieggii/ecbratex/pkg/xml"
 )
 
 // Provider is [provider.Provider] which will be used to fetch rates data across the ecbratex.
-// provider.HTTPProvider with URLs to the ECB website is used by default.
+// provider.HTTPProvider with URLs to the ECB website and the default retry policy is used by default.
 var Provider provider.Provider = provider.NewHTTPProvider(
 	"https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml",
 	"https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml",
 	"https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml",
+	provider.WithRetryPolicy(provider.DefaultRetryPolicy()),
 )
 
 // SetProvider sets data provider which will be used across the ecbratex to fetch exchange rates records.
@@ -23,6 +27,37 @@ func SetProvider(p provider.Provider) {
 	Provider = p
 }
 
+// FetchOption configures fetching of exchange rates.
+type FetchOption func(o *fetchOptions)
+
+// fetchOptions are options of the fetch functions.
+type fetchOptions struct {
+	strict         bool
+	validationOpts []validate.Option
+
+	allowStale bool
+	onStale    func(err *provider.StaleDataError)
+}
+
+// WithStrictValidation makes fetch functions validate fetched data using [validate.XMLData]
+// and fail with *validate.Error if any issues are found.
+func WithStrictValidation(opts ...validate.Option) FetchOption {
+	return func(o *fetchOptions) {
+		o.strict = true
+		o.validationOpts = opts
+	}
+}
+
+// WithStaleData makes fetch functions accept stale data served by Provider (see provider.StaleDataError)
+// instead of failing with *provider.StaleDataError.
+// If onStale is not nil, it is called with the error describing the stale data before records are returned.
+func WithStaleData(onStale func(err *provider.StaleDataError)) FetchOption {
+	return func(o *fetchOptions) {
+		o.allowStale = true
+		o.onStale = onStale
+	}
+}
+
 var ErrUnexpectedPeriod = errors.New("unexpected period")
 
 type Period uint8
@@ -45,79 +80,112 @@ func (p Period) DataKind() (provider.DataKind, error) {
 }
 
 // FetchLatest fetches latest available exchange rates using Provider.
-func FetchLatest() (*record.WithDate, error) {
-	rawData, err := Provider.GetRatesData(provider.DataKindLatest)
-	if err != nil {
-		return nil, err
-	}
-
-	xmlData, err := xml.NewData(rawData)
-	if err != nil {
-		return nil, err
-	}
+func FetchLatest(opts ...FetchOption) (*record.WithDate, error) {
+	return FetchLatestContext(context.Background(), opts...)
+}
 
-	return record.NewWithDateFromXMLData(xmlData)
+// FetchLatestContext fetches latest available exchange rates using Provider.
+// Fetching is aborted as soon as ctx is done.
+// Stale data served by Provider is rejected unless WithStaleData is used.
+func FetchLatestContext(ctx context.Context, opts ...FetchOption) (*record.WithDate, error) {
+	return fetchRecords(ctx, provider.DataKindLatest, record.NewWithDateFromXMLData, opts)
 }
 
 // FetchTimeSeries fetches rate records within the given period using Provider.
 // Returns records represented as timeseries.RecordsMap.
-func FetchTimeSeries(period Period) (timeseries.UnorderedRecords, error) {
+func FetchTimeSeries(period Period, opts ...FetchOption) (timeseries.UnorderedRecords, error) {
+	return FetchTimeSeriesContext(context.Background(), period, opts...)
+}
+
+// FetchTimeSeriesContext is like FetchTimeSeries, but aborts fetching as soon as ctx is done.
+// Stale data served by Provider is rejected unless WithStaleData is used.
+func FetchTimeSeriesContext(ctx context.Context, period Period, opts ...FetchOption) (timeseries.UnorderedRecords, error) {
 	dataKind, err := period.DataKind()
 	if err != nil {
 		return nil, err
 	}
 
-	rawData, err := Provider.GetRatesData(dataKind)
-	if err != nil {
-		return nil, err
-	}
+	return fetchRecords(ctx, dataKind, timeseries.NewUnorderedRecordsFromXML, opts)
+}
 
-	xmlData, err := xml.NewData(rawData)
+// FetchOrderedTimeSeries fetches rate records within the given period using Provider.
+// Returns records represented as timeseries.RecordsSlice.
+func FetchOrderedTimeSeries(period Period, opts ...FetchOption) (timeseries.OrderedRecords, error) {
+	return FetchOrderedTimeSeriesContext(context.Background(), period, opts...)
+}
+
+// FetchOrderedTimeSeriesContext is like FetchOrderedTimeSeries, but aborts fetching as soon as ctx is done.
+// Stale data served by Provider is rejected unless WithStaleData is used.
+func FetchOrderedTimeSeriesContext(ctx context.Context, period Period, opts ...FetchOption) (timeseries.OrderedRecords, error) {
+	dataKind, err := period.DataKind()
 	if err != nil {
 		return nil, err
 	}
 
-	return timeseries.NewUnorderedRecordsFromXML(xmlData)
+	return fetchRecords(ctx, dataKind, timeseries.NewOrderedRecordsFromXML, opts)
 }
 
-// FetchOrderedTimeSeries fetches rate records within the given period using Provider.
-// Returns records represented as timeseries.RecordsSlice.
-func FetchOrderedTimeSeries(period Period) (timeseries.OrderedRecords, error) {
+// FetchOrderedUnorderedTimeSeries fetches rate records within the given period using Provider.
+// Returns records represented as timeseries.RecordsSliceMap.
+func FetchOrderedUnorderedTimeSeries(period Period, opts ...FetchOption) (*timeseries.OrderedUnorderedRecords, error) {
+	return FetchOrderedUnorderedTimeSeriesContext(context.Background(), period, opts...)
+}
+
+// FetchOrderedUnorderedTimeSeriesContext is like FetchOrderedUnorderedTimeSeries,
+// but aborts fetching as soon as ctx is done.
+// Stale data served by Provider is rejected unless WithStaleData is used.
+func FetchOrderedUnorderedTimeSeriesContext(ctx context.Context, period Period, opts ...FetchOption) (*timeseries.OrderedUnorderedRecords, error) {
 	dataKind, err := period.DataKind()
 	if err != nil {
 		return nil, err
 	}
 
-	rawData, err := Provider.GetRatesData(dataKind)
-	if err != nil {
-		return nil, err
+	return fetchRecords(ctx, dataKind, timeseries.NewOrderedUnorderedRecordsFromXML, opts)
+}
+
+// fetchRecords fetches rates data of the given kind using Provider, decodes it and creates records using newRecords.
+// Data format is detected by decoder.Default using content type reported by Provider (if it implements
+// provider.ContentTypeProvider) or the data itself.
+// If strict validation is enabled, data containing any issues is rejected.
+// Stale data served by Provider is rejected unless it is allowed using WithStaleData.
+func fetchRecords[T any](ctx context.Context, kind provider.DataKind, newRecords func(xmlData *xml.Data) (T, error), opts []FetchOption) (T, error) {
+	var zero T
+
+	options := fetchOptions{}
+	for _, opt := range opts {
+		opt(&options)
 	}
 
-	xmlData, err := xml.NewData(rawData)
-	if err != nil {
-		return nil, err
+	p := Provider
+	rawData, err := provider.GetRatesDataContext(ctx, p, kind)
+	var staleErr *provider.StaleDataError
+	if err != nil && (!options.allowStale || !errors.As(err, &staleErr)) {
+		return zero, err
 	}
 
-	return timeseries.NewOrderedRecordsFromXML(xmlData)
-}
+	var contentType string
+	if ctp, ok := p.(provider.ContentTypeProvider); ok {
+		contentType, _ = ctp.ContentType(kind)
+	}
 
-// FetchOrderedUnorderedTimeSeries fetches rate records within the given period using Provider.
-// Returns records represented as timeseries.RecordsSliceMap.
-func FetchOrderedUnorderedTimeSeries(period Period) (*timeseries.OrderedUnorderedRecords, error) {
-	dataKind, err := period.DataKind()
+	xmlData, err := decoder.Decode(rawData, contentType)
 	if err != nil {
-		return nil, err
+		return zero, err
 	}
 
-	rawData, err := Provider.GetRatesData(dataKind)
-	if err != nil {
-		return nil, err
+	if options.strict {
+		if err := validate.XMLData(xmlData, options.validationOpts...).Err(); err != nil {
+			return zero, err
+		}
 	}
 
-	xmlData, err := xml.NewData(rawData)
+	records, err := newRecords(xmlData)
 	if err != nil {
-		return nil, err
+		return zero, err
 	}
 
-	return timeseries.NewOrderedUnorderedRecordsFromXML(xmlData)
+	if staleErr != nil && options.onStale != nil {
+		options.onStale(staleErr)
+	}
+	return records, nil
 }
@@ -1,12 +1,17 @@
 package ecbratex
 
 import (
+	"context"
+	"errors"
 	"github.com/jieggii/ecbratex/mocks"
 	"github.com/jieggii/ecbratex/pkg/provider"
 	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/validate"
 	"github.com/jieggii/ecbratex/tests"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"os"
+	"path"
 	"testing"
 )
 
@@ -311,3 +316,219 @@ func TestFetchOrderedUnorderedTimeSeries(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchContext(t *testing.T) {
+	var (
+		server       = tests.NewTestHTTPServer(testDataPath, false)
+		testProvider = provider.NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+	)
+	defer server.Close()
+
+	oldProvider := Provider
+	SetProvider(testProvider)
+	defer SetProvider(oldProvider)
+
+	t.Run("active context", func(t *testing.T) {
+		ctx := context.Background()
+
+		latest, err := FetchLatestContext(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, record.NewDate(2024, 2, 27), latest.Date)
+		}
+
+		unordered, err := FetchTimeSeriesContext(ctx, PeriodLast90Days)
+		if assert.NoError(t, err) {
+			assert.Len(t, unordered, expectedTimeSeriesLast90DaysDataLen)
+		}
+
+		ordered, err := FetchOrderedTimeSeriesContext(ctx, PeriodLast90Days)
+		if assert.NoError(t, err) {
+			assert.Len(t, ordered, expectedTimeSeriesLast90DaysDataLen)
+		}
+
+		orderedUnordered, err := FetchOrderedUnorderedTimeSeriesContext(ctx, PeriodLast90Days)
+		if assert.NoError(t, err) {
+			assert.Len(t, orderedUnordered.Dates, expectedTimeSeriesLast90DaysDataLen)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		latest, err := FetchLatestContext(ctx)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, latest)
+		}
+
+		unordered, err := FetchTimeSeriesContext(ctx, PeriodWhole)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, unordered)
+		}
+
+		ordered, err := FetchOrderedTimeSeriesContext(ctx, PeriodWhole)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, ordered)
+		}
+
+		orderedUnordered, err := FetchOrderedUnorderedTimeSeriesContext(ctx, PeriodWhole)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, orderedUnordered)
+		}
+	})
+}
+
+func TestFetchZip(t *testing.T) {
+	var testProvider = provider.NewFSProvider(
+		path.Join(testDataPath, "eurofxref.zip"),
+		path.Join(testDataPath, "eurofxref-hist.zip"),
+		path.Join(testDataPath, "eurofxref-hist.zip"),
+	)
+
+	oldProvider := Provider
+	SetProvider(testProvider)
+	defer SetProvider(oldProvider)
+
+	recWithDate, err := FetchLatest()
+	if assert.NoError(t, err) {
+		assert.Equal(t, record.NewDate(2024, 2, 27), recWithDate.Date)
+		assert.Len(t, recWithDate.Record, 31)
+	}
+
+	ordered, err := FetchOrderedTimeSeries(PeriodWhole)
+	if assert.NoError(t, err) {
+		assert.Len(t, ordered, expectedTimeSeriesDataLen)
+	}
+}
+
+func TestFetchStrict(t *testing.T) {
+	oldProvider := Provider
+	defer SetProvider(oldProvider)
+
+	t.Run("valid data", func(t *testing.T) {
+		SetProvider(provider.NewFSProvider(
+			path.Join(testDataPath, "eurofxref-daily.xml"),
+			path.Join(testDataPath, "eurofxref-hist.xml"),
+			path.Join(testDataPath, "eurofxref-hist-90d.xml"),
+		))
+
+		recWithDate, err := FetchLatest(WithStrictValidation())
+		if assert.NoError(t, err) {
+			assert.Equal(t, record.NewDate(2024, 2, 27), recWithDate.Date)
+		}
+
+		ordered, err := FetchOrderedTimeSeries(PeriodWhole, WithStrictValidation())
+		if assert.NoError(t, err) {
+			assert.Len(t, ordered, expectedTimeSeriesDataLen)
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		filePath := path.Join(t.TempDir(), "eurofxref-daily.json")
+		data := `{"records": [{"date": "2024-02-27", "rates": {"USD": 1.0856, "JPY": 0}}]}`
+		if err := os.WriteFile(filePath, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		SetProvider(provider.NewFSProvider(filePath, filePath, filePath))
+
+		_, err := FetchLatest()
+		assert.NoError(t, err)
+
+		var validationErr *validate.Error
+		recWithDate, err := FetchLatest(WithStrictValidation())
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, []validate.Issue{
+				{Kind: validate.IssueInvalidRate, Date: "2024-02-27", Currency: "JPY"},
+			}, validationErr.Report.Issues)
+		}
+		assert.Nil(t, recWithDate)
+	})
+}
+
+func TestFetchStaleData(t *testing.T) {
+	var (
+		dir           = t.TempDir()
+		freshProvider = provider.NewDiskCacheProvider(
+			provider.NewFSProvider(
+				path.Join(testDataPath, "eurofxref-daily.xml"),
+				path.Join(testDataPath, "eurofxref-hist.xml"),
+				path.Join(testDataPath, "eurofxref-hist-90d.xml"),
+			),
+			dir,
+		)
+		staleProvider = provider.NewDiskCacheProvider(mocks.NewBrokenProvider(), dir)
+	)
+
+	oldProvider := Provider
+	defer SetProvider(oldProvider)
+
+	// fill the cache:
+	SetProvider(freshProvider)
+	_, err := FetchLatest()
+	assert.NoError(t, err)
+	_, err = FetchTimeSeries(PeriodLast90Days)
+	assert.NoError(t, err)
+
+	SetProvider(staleProvider)
+
+	t.Run("stale data is rejected by default", func(t *testing.T) {
+		var staleErr *provider.StaleDataError
+
+		recWithDate, err := FetchLatest()
+		if assert.ErrorAs(t, err, &staleErr) {
+			assert.Nil(t, recWithDate)
+		}
+
+		unordered, err := FetchTimeSeries(PeriodLast90Days)
+		if assert.ErrorAs(t, err, &staleErr) {
+			assert.Nil(t, unordered)
+		}
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		var staleErr *provider.StaleDataError
+
+		recWithDate, err := FetchLatest(WithStaleData(func(err *provider.StaleDataError) {
+			staleErr = err
+		}))
+		if assert.NoError(t, err) {
+			assert.Equal(t, record.NewDate(2024, 2, 27), recWithDate.Date)
+			if assert.NotNil(t, staleErr) {
+				assert.Equal(t, provider.DataKindLatest, staleErr.Kind)
+			}
+		}
+	})
+
+	t.Run("time series", func(t *testing.T) {
+		var staleErrs []*provider.StaleDataError
+		onStale := func(err *provider.StaleDataError) {
+			staleErrs = append(staleErrs, err)
+		}
+
+		unordered, err := FetchTimeSeries(PeriodLast90Days, WithStaleData(onStale))
+		if assert.NoError(t, err) {
+			assert.Len(t, unordered, expectedTimeSeriesLast90DaysDataLen)
+		}
+
+		ordered, err := FetchOrderedTimeSeries(PeriodLast90Days, WithStaleData(onStale))
+		if assert.NoError(t, err) {
+			assert.Len(t, ordered, expectedTimeSeriesLast90DaysDataLen)
+		}
+
+		orderedUnordered, err := FetchOrderedUnorderedTimeSeries(PeriodLast90Days, WithStaleData(nil))
+		if assert.NoError(t, err) {
+			assert.Len(t, orderedUnordered.Dates, expectedTimeSeriesLast90DaysDataLen)
+		}
+
+		assert.Len(t, staleErrs, 2)
+	})
+
+	t.Run("nothing is cached", func(t *testing.T) {
+		data, err := FetchTimeSeries(PeriodWhole, WithStaleData(nil))
+		if assert.Error(t, err) {
+			var staleErr *provider.StaleDataError
+			assert.False(t, errors.As(err, &staleErr))
+			assert.Empty(t, data)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"github.com/jieggii/ecbratex/pkg/provider"
 )
@@ -12,8 +13,15 @@ func NewBrokenProvider() *BrokenProvider {
 }
 
 func (p *BrokenProvider) GetRatesData(kind provider.DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+func (p *BrokenProvider) GetRatesDataContext(ctx context.Context, kind provider.DataKind) ([]byte, error) {
 	if kind != provider.DataKindLatest && kind != provider.DataKindTimeSeries && kind != provider.DataKindTimeSeriesLast90Days {
 		return nil, provider.ErrUnexpectedDataKind
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return nil, errors.New("I failed again")
 }
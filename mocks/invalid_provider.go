@@ -1,6 +1,9 @@
 package mocks
 
-import "github.com/jieggii/ecbratex/pkg/provider"
+import (
+	"context"
+	"github.com/jieggii/ecbratex/pkg/provider"
+)
 
 type InvalidProvider struct{}
 
@@ -9,8 +12,15 @@ func NewInvalidProvider() *InvalidProvider {
 }
 
 func (p *InvalidProvider) GetRatesData(kind provider.DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+func (p *InvalidProvider) GetRatesDataContext(ctx context.Context, kind provider.DataKind) ([]byte, error) {
 	if kind != provider.DataKindLatest && kind != provider.DataKindTimeSeries && kind != provider.DataKindTimeSeriesLast90Days {
 		return nil, provider.ErrUnexpectedDataKind
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return []byte("some invalid data"), nil
 }
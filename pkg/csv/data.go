@@ -0,0 +1,152 @@
+package csv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoCSVFile error indicates that zip archive does not contain any CSV file.
+	ErrNoCSVFile = errors.New("zip archive does not contain any CSV file")
+
+	// ErrUnexpectedHeader error indicates that CSV header does not match the ECB format.
+	ErrUnexpectedHeader = errors.New("unexpected CSV header")
+)
+
+// notAvailable is the value of cells containing no rate.
+const notAvailable = "N/A"
+
+// dateLayouts are layouts of dates used in the ECB CSV files:
+// time series files use "2006-01-02", while the latest rates file uses "02 January 2006".
+var dateLayouts = []string{time.DateOnly, "02 January 2006"}
+
+// NewData decodes CSV bytes in the ECB format to a new [xml.Data].
+//
+// The first row is the header containing "Date" followed by currency codes,
+// every next row contains date followed by rates. Cells containing "N/A" and empty cells
+// (including the trailing empty column) are skipped.
+func NewData(data []byte) (*xml.Data, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	currencies, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	xmlData := &xml.Data{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cube, err := parseRow(row, currencies)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		xmlData.Cubes = append(xmlData.Cubes, cube)
+	}
+
+	return xmlData, nil
+}
+
+// NewDataFromZip extracts the first CSV file from zip archive bytes and decodes it to a new [xml.Data].
+func NewDataFromZip(data []byte) (*xml.Data, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range archive.File {
+		if !strings.EqualFold(path.Ext(file.Name), ".csv") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		csvData, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("extract %s: %w", file.Name, err)
+		}
+
+		return NewData(csvData)
+	}
+
+	return nil, ErrNoCSVFile
+}
+
+// parseHeader returns currencies of the header columns.
+// Currency of the date column and of empty columns is empty.
+func parseHeader(header []string) ([]string, error) {
+	if len(header) == 0 || strings.TrimSpace(header[0]) != "Date" {
+		return nil, ErrUnexpectedHeader
+	}
+
+	currencies := make([]string, len(header))
+	for i, cell := range header[1:] {
+		currencies[i+1] = strings.TrimSpace(cell)
+	}
+	return currencies, nil
+}
+
+// parseRow parses rates row to [xml.DataCube].
+func parseRow(row []string, currencies []string) (xml.DataCube, error) {
+	date, err := parseDate(row[0])
+	if err != nil {
+		return xml.DataCube{}, err
+	}
+
+	cube := xml.DataCube{Date: date}
+	for i, cell := range row[1:] {
+		i++ // index of the cell in the row
+
+		cell = strings.TrimSpace(cell)
+		if cell == "" || cell == notAvailable {
+			continue
+		}
+		if i >= len(currencies) || currencies[i] == "" {
+			return xml.DataCube{}, fmt.Errorf("rate %q in column %d without currency", cell, i+1)
+		}
+
+		rate, err := strconv.ParseFloat(cell, 32)
+		if err != nil {
+			return xml.DataCube{}, fmt.Errorf("parse %s rate: %w", currencies[i], err)
+		}
+		cube.Rates = append(cube.Rates, xml.DataCubeRate{Currency: currencies[i], Rate: float32(rate), Value: cell})
+	}
+
+	return cube, nil
+}
+
+// parseDate parses date in one of the dateLayouts and returns it in "YYYY-MM-DD" format.
+func parseDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format(time.DateOnly), nil
+		}
+	}
+	return "", fmt.Errorf("parse date %q: unexpected format", value)
+}
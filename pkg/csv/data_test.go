@@ -0,0 +1,138 @@
+package csv
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+const testDataPath = "./../../testdata"
+
+func readTestFile(name string) []byte {
+	data, err := os.ReadFile(testDataPath + "/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestNewData(t *testing.T) {
+	t.Run("time series CSV", func(t *testing.T) {
+		data := []byte("Date,USD,JPY,CYP,\n2024-02-27,1.0856,163.04,N/A,\n2024-02-26,1.0852,163.38,N/A,\n")
+
+		csvData, err := NewData(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &xml.Data{Cubes: []xml.DataCube{
+				{Date: "2024-02-27", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 1.0856, Value: "1.0856"}, {Currency: "JPY", Rate: 163.04, Value: "163.04"}}},
+				{Date: "2024-02-26", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 1.0852, Value: "1.0852"}, {Currency: "JPY", Rate: 163.38, Value: "163.38"}}},
+			}}, csvData)
+		}
+	})
+
+	t.Run("latest CSV", func(t *testing.T) {
+		data := []byte("Date, USD, JPY, \n27 February 2024, 1.0856, 163.04, \n")
+
+		csvData, err := NewData(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &xml.Data{Cubes: []xml.DataCube{
+				{Date: "2024-02-27", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 1.0856, Value: "1.0856"}, {Currency: "JPY", Rate: 163.04, Value: "163.04"}}},
+			}}, csvData)
+		}
+	})
+
+	t.Run("header only", func(t *testing.T) {
+		csvData, err := NewData([]byte("Date,USD,\n"))
+		if assert.NoError(t, err) {
+			assert.Empty(t, csvData.Cubes)
+		}
+	})
+
+	t.Run("empty data", func(t *testing.T) {
+		csvData, err := NewData(nil)
+		if assert.Error(t, err) {
+			assert.Empty(t, csvData)
+		}
+	})
+
+	t.Run("unexpected header", func(t *testing.T) {
+		csvData, err := NewData([]byte("Currency,USD\n2024-02-27,1.0856\n"))
+		if assert.ErrorIs(t, err, ErrUnexpectedHeader) {
+			assert.Empty(t, csvData)
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		csvData, err := NewData([]byte("Date,USD\n27.02.2024,1.0856\n"))
+		if assert.Error(t, err) {
+			assert.Empty(t, csvData)
+		}
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		csvData, err := NewData([]byte("Date,USD\n2024-02-27,one\n"))
+		if assert.Error(t, err) {
+			assert.Empty(t, csvData)
+		}
+	})
+
+	t.Run("rate without currency", func(t *testing.T) {
+		csvData, err := NewData([]byte("Date,USD\n2024-02-27,1.0856,1.1\n"))
+		if assert.Error(t, err) {
+			assert.Empty(t, csvData)
+		}
+	})
+}
+
+func TestNewDataFromZip(t *testing.T) {
+	t.Run("time series zip matches time series XML", func(t *testing.T) {
+		csvData, err := NewDataFromZip(readTestFile("eurofxref-hist.zip"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		xmlData, err := xml.NewData(readTestFile("eurofxref-hist.xml"))
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(t, xmlData.Cubes, csvData.Cubes)
+	})
+
+	t.Run("latest zip matches latest XML", func(t *testing.T) {
+		csvData, err := NewDataFromZip(readTestFile("eurofxref.zip"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		xmlData, err := xml.NewData(readTestFile("eurofxref-daily.xml"))
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(t, xmlData.Cubes, csvData.Cubes)
+	})
+
+	t.Run("zip without CSV file", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := zip.NewWriter(&buf)
+		if _, err := writer.Create("README.txt"); err != nil {
+			panic(err)
+		}
+		if err := writer.Close(); err != nil {
+			panic(err)
+		}
+
+		csvData, err := NewDataFromZip(buf.Bytes())
+		if assert.ErrorIs(t, err, ErrNoCSVFile) {
+			assert.Empty(t, csvData)
+		}
+	})
+
+	t.Run("invalid zip", func(t *testing.T) {
+		csvData, err := NewDataFromZip([]byte("invalid zip data"))
+		if assert.Error(t, err) {
+			assert.Empty(t, csvData)
+		}
+	})
+}
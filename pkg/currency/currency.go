@@ -0,0 +1,210 @@
+// Package currency provides ISO 4217 metadata of currencies the ECB has ever published reference rates for.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownCurrency error indicates that currency is not known.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency is ISO 4217 alphabetic code of a currency, for example USD.
+// Use constants or Parse to get a valid Currency.
+type Currency string
+
+// Currencies published by the ECB, including euro and withdrawn currencies.
+const (
+	AUD Currency = "AUD" // Australian dollar
+	BGN Currency = "BGN" // Bulgarian lev
+	BRL Currency = "BRL" // Brazilian real
+	CAD Currency = "CAD" // Canadian dollar
+	CHF Currency = "CHF" // Swiss franc
+	CNY Currency = "CNY" // Chinese yuan renminbi
+	CYP Currency = "CYP" // Cyprus pound
+	CZK Currency = "CZK" // Czech koruna
+	DKK Currency = "DKK" // Danish krone
+	EEK Currency = "EEK" // Estonian kroon
+	EUR Currency = "EUR" // Euro
+	GBP Currency = "GBP" // Pound sterling
+	HKD Currency = "HKD" // Hong Kong dollar
+	HRK Currency = "HRK" // Croatian kuna
+	HUF Currency = "HUF" // Hungarian forint
+	IDR Currency = "IDR" // Indonesian rupiah
+	ILS Currency = "ILS" // Israeli shekel
+	INR Currency = "INR" // Indian rupee
+	ISK Currency = "ISK" // Icelandic krona
+	JPY Currency = "JPY" // Japanese yen
+	KRW Currency = "KRW" // South Korean won
+	LTL Currency = "LTL" // Lithuanian litas
+	LVL Currency = "LVL" // Latvian lats
+	MTL Currency = "MTL" // Maltese lira
+	MXN Currency = "MXN" // Mexican peso
+	MYR Currency = "MYR" // Malaysian ringgit
+	NOK Currency = "NOK" // Norwegian krone
+	NZD Currency = "NZD" // New Zealand dollar
+	PHP Currency = "PHP" // Philippine peso
+	PLN Currency = "PLN" // Polish zloty
+	ROL Currency = "ROL" // Romanian leu (old)
+	RON Currency = "RON" // Romanian leu
+	RUB Currency = "RUB" // Russian rouble
+	SEK Currency = "SEK" // Swedish krona
+	SGD Currency = "SGD" // Singapore dollar
+	SIT Currency = "SIT" // Slovenian tolar
+	SKK Currency = "SKK" // Slovak koruna
+	THB Currency = "THB" // Thai baht
+	TRL Currency = "TRL" // Turkish lira (old)
+	TRY Currency = "TRY" // Turkish lira
+	USD Currency = "USD" // US dollar
+	ZAR Currency = "ZAR" // South African rand
+)
+
+// Parse parses currency code case-insensitively ignoring surrounding whitespace,
+// for example " usd" is parsed as USD.
+// Returns ErrUnknownCurrency if the currency is not known.
+func Parse(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if _, found := infos[c]; !found {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, s)
+	}
+	return c, nil
+}
+
+// MustParse is like Parse, but panics if s can not be parsed.
+func MustParse(s string) Currency {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Codes parses the given currencies, see Parse, and returns their alphabetic codes,
+// for example Currency("usd") is returned as "USD".
+// Returns ErrUnknownCurrency if any of the currencies is not known.
+func Codes(currencies ...Currency) ([]string, error) {
+	codes := make([]string, len(currencies))
+	for i, c := range currencies {
+		parsed, err := Parse(string(c))
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = string(parsed)
+	}
+	return codes, nil
+}
+
+// String returns the alphabetic code.
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Info returns metadata of the currency and a boolean indicating whether the currency is known.
+func (c Currency) Info() (Info, bool) {
+	info, found := infos[c]
+	return info, found
+}
+
+// Exponent returns minor-unit exponent of the currency or ErrUnknownCurrency if the currency is not known.
+func (c Currency) Exponent() (int, error) {
+	return Exponent(string(c))
+}
+
+// Info is ISO 4217 metadata of a currency.
+type Info struct {
+	// Code is the alphabetic code, for example USD.
+	Code Currency
+
+	// Numeric is the numeric code, for example 840.
+	Numeric uint16
+
+	// Name is the English name of the currency.
+	Name string
+
+	// Exponent is the number of digits after the decimal separator (minor-unit exponent),
+	// for example 2 for USD (1 dollar is 100 cents) or 0 for JPY.
+	Exponent int
+}
+
+// MinorUnitsPerMajor returns number of minor units in a major unit, for example 100 for USD.
+func (i Info) MinorUnitsPerMajor() int64 {
+	return int64(math.Pow10(i.Exponent))
+}
+
+// infos contains metadata of all currencies published by the ECB, including euro and withdrawn currencies.
+var infos = map[Currency]Info{
+	AUD: {Code: AUD, Numeric: 36, Name: "Australian dollar", Exponent: 2},
+	BGN: {Code: BGN, Numeric: 975, Name: "Bulgarian lev", Exponent: 2},
+	BRL: {Code: BRL, Numeric: 986, Name: "Brazilian real", Exponent: 2},
+	CAD: {Code: CAD, Numeric: 124, Name: "Canadian dollar", Exponent: 2},
+	CHF: {Code: CHF, Numeric: 756, Name: "Swiss franc", Exponent: 2},
+	CNY: {Code: CNY, Numeric: 156, Name: "Chinese yuan renminbi", Exponent: 2},
+	CYP: {Code: CYP, Numeric: 196, Name: "Cyprus pound", Exponent: 2},
+	CZK: {Code: CZK, Numeric: 203, Name: "Czech koruna", Exponent: 2},
+	DKK: {Code: DKK, Numeric: 208, Name: "Danish krone", Exponent: 2},
+	EEK: {Code: EEK, Numeric: 233, Name: "Estonian kroon", Exponent: 2},
+	EUR: {Code: EUR, Numeric: 978, Name: "Euro", Exponent: 2},
+	GBP: {Code: GBP, Numeric: 826, Name: "Pound sterling", Exponent: 2},
+	HKD: {Code: HKD, Numeric: 344, Name: "Hong Kong dollar", Exponent: 2},
+	HRK: {Code: HRK, Numeric: 191, Name: "Croatian kuna", Exponent: 2},
+	HUF: {Code: HUF, Numeric: 348, Name: "Hungarian forint", Exponent: 2},
+	IDR: {Code: IDR, Numeric: 360, Name: "Indonesian rupiah", Exponent: 2},
+	ILS: {Code: ILS, Numeric: 376, Name: "Israeli shekel", Exponent: 2},
+	INR: {Code: INR, Numeric: 356, Name: "Indian rupee", Exponent: 2},
+	ISK: {Code: ISK, Numeric: 352, Name: "Icelandic krona", Exponent: 0},
+	JPY: {Code: JPY, Numeric: 392, Name: "Japanese yen", Exponent: 0},
+	KRW: {Code: KRW, Numeric: 410, Name: "South Korean won", Exponent: 0},
+	LTL: {Code: LTL, Numeric: 440, Name: "Lithuanian litas", Exponent: 2},
+	LVL: {Code: LVL, Numeric: 428, Name: "Latvian lats", Exponent: 2},
+	MTL: {Code: MTL, Numeric: 470, Name: "Maltese lira", Exponent: 2},
+	MXN: {Code: MXN, Numeric: 484, Name: "Mexican peso", Exponent: 2},
+	MYR: {Code: MYR, Numeric: 458, Name: "Malaysian ringgit", Exponent: 2},
+	NOK: {Code: NOK, Numeric: 578, Name: "Norwegian krone", Exponent: 2},
+	NZD: {Code: NZD, Numeric: 554, Name: "New Zealand dollar", Exponent: 2},
+	PHP: {Code: PHP, Numeric: 608, Name: "Philippine peso", Exponent: 2},
+	PLN: {Code: PLN, Numeric: 985, Name: "Polish zloty", Exponent: 2},
+	ROL: {Code: ROL, Numeric: 642, Name: "Romanian leu (old)", Exponent: 2},
+	RON: {Code: RON, Numeric: 946, Name: "Romanian leu", Exponent: 2},
+	RUB: {Code: RUB, Numeric: 643, Name: "Russian rouble", Exponent: 2},
+	SEK: {Code: SEK, Numeric: 752, Name: "Swedish krona", Exponent: 2},
+	SGD: {Code: SGD, Numeric: 702, Name: "Singapore dollar", Exponent: 2},
+	SIT: {Code: SIT, Numeric: 705, Name: "Slovenian tolar", Exponent: 2},
+	SKK: {Code: SKK, Numeric: 703, Name: "Slovak koruna", Exponent: 2},
+	THB: {Code: THB, Numeric: 764, Name: "Thai baht", Exponent: 2},
+	TRL: {Code: TRL, Numeric: 792, Name: "Turkish lira (old)", Exponent: 0},
+	TRY: {Code: TRY, Numeric: 949, Name: "Turkish lira", Exponent: 2},
+	USD: {Code: USD, Numeric: 840, Name: "US dollar", Exponent: 2},
+	ZAR: {Code: ZAR, Numeric: 710, Name: "South African rand", Exponent: 2},
+}
+
+// Lookup returns metadata of the currency with the given alphabetic code
+// and a boolean indicating whether the currency is known.
+func Lookup(code string) (Info, bool) {
+	info, found := infos[Currency(code)]
+	return info, found
+}
+
+// Exponent returns minor-unit exponent of the currency with the given alphabetic code
+// or ErrUnknownCurrency if the currency is not known.
+func Exponent(code string) (int, error) {
+	info, found := infos[Currency(code)]
+	if !found {
+		return 0, ErrUnknownCurrency
+	}
+	return info.Exponent, nil
+}
+
+// All returns metadata of all known currencies ordered by code.
+func All() []Info {
+	all := make([]Info, 0, len(infos))
+	for _, info := range infos {
+		all = append(all, info)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Code < all[j].Code
+	})
+	return all
+}
@@ -0,0 +1,124 @@
+package currency
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	info, found := Lookup("JPY")
+	if assert.True(t, found) {
+		assert.Equal(t, Info{Code: "JPY", Numeric: 392, Name: "Japanese yen", Exponent: 0}, info)
+		assert.Equal(t, int64(1), info.MinorUnitsPerMajor())
+	}
+
+	info, found = Lookup("EUR")
+	if assert.True(t, found) {
+		assert.Equal(t, 2, info.Exponent)
+		assert.Equal(t, int64(100), info.MinorUnitsPerMajor())
+	}
+
+	_, found = Lookup("usd")
+	assert.False(t, found)
+}
+
+func TestExponent(t *testing.T) {
+	tests := map[string]int{"USD": 2, "EUR": 2, "JPY": 0, "KRW": 0, "ISK": 0, "IDR": 2}
+	for code, expected := range tests {
+		exponent, err := Exponent(code)
+		if assert.NoError(t, err) {
+			assert.Equalf(t, expected, exponent, "%s exponent", code)
+		}
+	}
+
+	_, err := Exponent("XXX")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+
+	numerics := make(map[uint16]Currency)
+	for i, info := range all {
+		if i > 0 {
+			assert.Less(t, all[i-1].Code, info.Code)
+		}
+		assert.Len(t, info.Code, 3)
+		assert.NotEmpty(t, info.Name)
+
+		if code, found := numerics[info.Numeric]; found {
+			t.Errorf("numeric code %d is used by both %s and %s", info.Numeric, code, info.Code)
+		}
+		numerics[info.Numeric] = info.Code
+	}
+
+	t.Run("all currencies of the test data are known", func(t *testing.T) {
+		data, err := os.ReadFile("./../../testdata/eurofxref-hist.xml")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, match := range regexp.MustCompile(`currency="([A-Z]{3})"`).FindAllSubmatch(data, -1) {
+			_, found := Lookup(string(match[1]))
+			assert.Truef(t, found, "currency %s", match[1])
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]Currency{"USD": USD, "usd": USD, " Eur\n": EUR, "jpy": JPY, "TRL": TRL}
+	for s, expected := range tests {
+		t.Run(s, func(t *testing.T) {
+			c, err := Parse(s)
+			if assert.NoError(t, err) {
+				assert.Equal(t, expected, c)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "EURO", "US", "XXX", "U SD"} {
+		t.Run(s, func(t *testing.T) {
+			c, err := Parse(s)
+			assert.ErrorIs(t, err, ErrUnknownCurrency)
+			assert.Empty(t, c)
+		})
+	}
+
+	assert.Panics(t, func() {
+		MustParse("EURO")
+	})
+}
+
+func TestCodes(t *testing.T) {
+	codes, err := Codes(USD, Currency("eur"), Currency(" jpy"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"USD", "EUR", "JPY"}, codes)
+	}
+
+	codes, err = Codes(USD, Currency("EURO"))
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	assert.Nil(t, codes)
+}
+
+func TestCurrency(t *testing.T) {
+	assert.Equal(t, "USD", USD.String())
+
+	info, found := KRW.Info()
+	if assert.True(t, found) {
+		assert.Equal(t, "South Korean won", info.Name)
+	}
+
+	exponent, err := ISK.Exponent()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, exponent)
+	}
+
+	_, err = Currency("usd").Exponent()
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	for _, info := range All() {
+		assert.Equal(t, info.Code, MustParse(string(info.Code)))
+	}
+}
@@ -0,0 +1,279 @@
+// Package decimal provides arbitrary-precision decimal numbers used for exact currency conversions.
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/rounding"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidSyntax error indicates that string does not represent a decimal number.
+	ErrInvalidSyntax = errors.New("invalid decimal syntax")
+
+	// ErrNotFinite error indicates that float is NaN or infinity, which can not be represented as Decimal.
+	ErrNotFinite = errors.New("float is not finite")
+
+	// ErrOverflow error indicates that decimal value does not fit into int64.
+	ErrOverflow = errors.New("decimal value overflows int64")
+)
+
+// DivisionScale is the default number of fractional digits quotients are rounded to.
+const DivisionScale = 16
+
+// Decimal is an arbitrary-precision decimal number, which value is coef * 10^exp.
+// Decimal is immutable, all operations return new values. The zero value is 0.
+type Decimal struct {
+	coef *big.Int
+	exp  int32
+}
+
+// Zero is the zero Decimal.
+var Zero = Decimal{}
+
+// New creates a new Decimal equal to coef * 10^exp.
+// For example, New(10856, -4) is 1.0856.
+func New(coef int64, exp int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), exp: exp}
+}
+
+// NewFromInt creates a new Decimal equal to the given integer.
+func NewFromInt(value int64) Decimal {
+	return New(value, 0)
+}
+
+// NewFromBigInt creates a new Decimal equal to coef * 10^exp.
+// The coefficient is copied.
+func NewFromBigInt(coef *big.Int, exp int32) Decimal {
+	return Decimal{coef: new(big.Int).Set(coef), exp: exp}
+}
+
+// NewFromFloat32 creates a new Decimal from the shortest decimal representation of the given float32,
+// for example 1.0856 for float32(1.0856).
+// Decimals with no more than 6 significant digits are recovered exactly, longer ones may be not:
+// for example, float32(8589973000) is recovered as 8589974000.
+func NewFromFloat32(value float32) (Decimal, error) {
+	if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+		return Decimal{}, ErrNotFinite
+	}
+	return Parse(strconv.FormatFloat(float64(value), 'g', -1, 32))
+}
+
+// Parse parses decimal number from string in "[+-]digits[.digits][e[+-]digits]" format,
+// for example "1.0856", "-100" or "1.5e-3".
+func Parse(s string) (Decimal, error) {
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(s), "e")
+
+	var exp int64
+	if hasExponent {
+		var err error
+		exp, err = strconv.ParseInt(exponent, 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" || digits == "+" || digits == "-" || strings.ContainsAny(fracPart, "+-") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+	}
+
+	exp -= int64(len(fracPart))
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+	}
+	return Decimal{coef: coef, exp: int32(exp)}, nil
+}
+
+// MustParse is like Parse, but panics if s can not be parsed.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// coefficient returns the coefficient treating nil as zero.
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// Exponent returns exponent of the decimal.
+func (d Decimal) Exponent() int32 {
+	return d.exp
+}
+
+// Coefficient returns a copy of coefficient of the decimal.
+func (d Decimal) Coefficient() *big.Int {
+	return new(big.Int).Set(d.coefficient())
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and +1 if d > 0.
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+// IsZero reports whether d == 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), exp: d.exp}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), exp: d.exp}
+}
+
+// align returns coefficients of d and other rescaled to the same (the smallest) exponent.
+func align(d Decimal, other Decimal) (*big.Int, *big.Int, int32) {
+	a, b := d.coefficient(), other.coefficient()
+	switch {
+	case d.exp > other.exp:
+		return new(big.Int).Mul(a, pow10(int64(d.exp)-int64(other.exp))), b, other.exp
+	case d.exp < other.exp:
+		return a, new(big.Int).Mul(b, pow10(int64(other.exp)-int64(d.exp))), d.exp
+	default:
+		return a, b, d.exp
+	}
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, exp := align(d, other)
+	return Decimal{coef: new(big.Int).Add(a, b), exp: exp}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, exp := align(d, other)
+	return Decimal{coef: new(big.Int).Sub(a, b), exp: exp}
+}
+
+// Mul returns d * other. The result is exact.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.coefficient(), other.coefficient()), exp: d.exp + other.exp}
+}
+
+// Quo returns d / other rounded half away from zero to the given number of fractional digits.
+// Panics if other is zero.
+func (d Decimal) Quo(other Decimal, scale int32) Decimal {
+	return d.QuoMode(other, scale, rounding.HalfAwayFromZero)
+}
+
+// QuoMode returns d / other rounded using the given mode to the given number of fractional digits.
+// Panics if other is zero.
+func (d Decimal) QuoMode(other Decimal, scale int32, mode rounding.Mode) Decimal {
+	// d / other = (d.coef / other.coef) * 10^(d.exp - other.exp),
+	// the result coefficient is d.coef * 10^(d.exp - other.exp + scale) / other.coef:
+	num := d.coefficient()
+	den := other.coefficient()
+	if den.Sign() == 0 {
+		panic("decimal: division by zero")
+	}
+	return Decimal{coef: quoScaled(num, den, int64(d.exp)-int64(other.exp)+int64(scale), mode), exp: -scale}
+}
+
+// Round returns d rounded half away from zero to the given number of fractional digits.
+func (d Decimal) Round(scale int32) Decimal {
+	return d.RoundMode(scale, rounding.HalfAwayFromZero)
+}
+
+// RoundMode returns d rounded using the given mode to the given number of fractional digits.
+func (d Decimal) RoundMode(scale int32, mode rounding.Mode) Decimal {
+	if d.exp >= -scale {
+		return d
+	}
+	return Decimal{coef: quoScaled(d.coefficient(), big.NewInt(1), int64(d.exp)+int64(scale), mode), exp: -scale}
+}
+
+// Cmp compares d and other and returns -1 if d < other, 0 if d == other and +1 if d > other.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other represent the same number regardless of their exponents.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Int64 returns integer part of d (truncated toward zero) and ErrOverflow if it does not fit into int64.
+func (d Decimal) Int64() (int64, error) {
+	var value *big.Int
+	if d.exp >= 0 {
+		value = new(big.Int).Mul(d.coefficient(), pow10(int64(d.exp)))
+	} else {
+		value = new(big.Int).Quo(d.coefficient(), pow10(-int64(d.exp)))
+	}
+	if !value.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return value.Int64(), nil
+}
+
+// Rat returns d as a new big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	if d.exp >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(d.coefficient(), pow10(int64(d.exp))))
+	}
+	return new(big.Rat).SetFrac(d.coefficient(), pow10(-int64(d.exp)))
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String returns d in plain notation keeping all fractional digits, for example "-1.0850".
+func (d Decimal) String() string {
+	coef := d.coefficient()
+	if d.exp >= 0 {
+		return new(big.Int).Mul(coef, pow10(int64(d.exp))).String()
+	}
+
+	digits := new(big.Int).Abs(coef).String()
+	scale := int(-d.exp)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if coef.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// quoScaled returns num * 10^shift / den rounded using the given mode.
+func quoScaled(num *big.Int, den *big.Int, shift int64, mode rounding.Mode) *big.Int {
+	if shift >= 0 {
+		num = new(big.Int).Mul(num, pow10(shift))
+	} else {
+		den = new(big.Int).Mul(den, pow10(-shift))
+	}
+	return mode.RoundQuo(num, den)
+}
+
+// pow10 returns 10^n for n >= 0.
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
@@ -0,0 +1,188 @@
+package decimal
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		{"0", "0"},
+		{"1.0856", "1.0856"},
+		{"-1.0856", "-1.0856"},
+		{"+100", "100"},
+		{"16976.12", "16976.12"},
+		{"0.85620", "0.85620"},
+		{".5", "0.5"},
+		{"-.5", "-0.5"},
+		{"1.5e-3", "0.0015"},
+		{"1.5E3", "1500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			d, err := Parse(tt.s)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, d.String())
+			}
+		})
+	}
+
+	for _, s := range []string{"", ".", "-", "+.", "1.2.3", "1.-2", "1e", "1e1.5", "abc", "1,5", " 1"} {
+		t.Run("invalid "+strconv.Quote(s), func(t *testing.T) {
+			_, err := Parse(s)
+			assert.ErrorIs(t, err, ErrInvalidSyntax)
+		})
+	}
+}
+
+func TestNewFromFloat32(t *testing.T) {
+	t.Run("up to 6 significant digits", func(t *testing.T) {
+		for _, s := range []string{"1.0856", "163.04", "0.85495", "7.4543", "16911.5", "999999", "1e-7"} {
+			value, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				panic(err)
+			}
+			actual, err := NewFromFloat32(float32(value))
+			if assert.NoError(t, err) {
+				assert.True(t, MustParse(s).Equal(actual), "value %s, actual %s", s, actual)
+			}
+		}
+	})
+
+	t.Run("all rates of the test data", func(t *testing.T) {
+		data, err := os.ReadFile("./../../testdata/eurofxref-hist.xml")
+		if err != nil {
+			panic(err)
+		}
+
+		matches := regexp.MustCompile(`rate=['"]([0-9.]+)['"]`).FindAllSubmatch(data, -1)
+		assert.NotEmpty(t, matches)
+		for _, match := range matches {
+			expected := MustParse(string(match[1]))
+
+			rate, err := strconv.ParseFloat(string(match[1]), 32)
+			if err != nil {
+				panic(err)
+			}
+			actual, err := NewFromFloat32(float32(rate))
+			if assert.NoError(t, err) && !assert.True(t, expected.Equal(actual), "rate %s", match[1]) {
+				return
+			}
+		}
+	})
+
+	t.Run("more significant digits", func(t *testing.T) {
+		actual, err := NewFromFloat32(8589973000)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "8589974000", actual.String())
+		}
+	})
+
+	t.Run("not finite", func(t *testing.T) {
+		_, err := NewFromFloat32(float32(math.NaN()))
+		assert.ErrorIs(t, err, ErrNotFinite)
+
+		_, err = NewFromFloat32(float32(math.Inf(1)))
+		assert.ErrorIs(t, err, ErrNotFinite)
+	})
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := MustParse("1.0856"), MustParse("-2.5")
+
+	assert.Equal(t, "-1.4144", a.Add(b).String())
+	assert.Equal(t, "3.5856", a.Sub(b).String())
+	assert.Equal(t, "-2.71400", a.Mul(b).String())
+	assert.Equal(t, "-0.4342", a.Quo(b, 4).String())
+	assert.Equal(t, "1.0856", a.Abs().String())
+	assert.Equal(t, "2.5", b.Neg().String())
+	assert.Equal(t, "0", Zero.String())
+	assert.Equal(t, "1", Zero.Add(NewFromInt(1)).String())
+	assert.True(t, Zero.IsZero())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Cmp(a))
+	assert.True(t, MustParse("1.50").Equal(MustParse("1.5")))
+	assert.InDelta(t, 1.0856, a.Float64(), 1e-15)
+
+	assert.Panics(t, func() {
+		a.Quo(Zero, 2)
+	})
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		value    string
+		scale    int32
+		expected string
+	}{
+		{"1.25", 1, "1.3"},
+		{"1.35", 1, "1.4"},
+		{"-1.25", 1, "-1.3"},
+		{"1.249", 1, "1.2"},
+		{"-1.249", 1, "-1.2"},
+		{"0.5", 0, "1"},
+		{"-0.5", 0, "-1"},
+		{"1.2", 3, "1.2"},
+		{"125", -1, "130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MustParse(tt.value).Round(tt.scale).String())
+		})
+	}
+}
+
+func TestDecimal_Int64(t *testing.T) {
+	value, err := MustParse("-123.9").Int64()
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(-123), value)
+	}
+
+	value, err = MustParse("1.5e3").Int64()
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1500), value)
+	}
+
+	_, err = MustParse("1e19").Int64()
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+// roundRat rounds r half away from zero to the given number of fractional digits.
+func roundRat(r *big.Rat, scale int64) *big.Rat {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+	num, den := scaled.Num(), scaled.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Lsh(new(big.Int).Abs(rem), 1).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+	return new(big.Rat).SetFrac(quo, pow10(scale))
+}
+
+func TestDecimal_Quo_Property(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 10000; i++ {
+		a := New(random.Int64N(2_000_000_000_000)-1_000_000_000_000, -int32(random.IntN(8)))
+		b := New(random.Int64N(2_000_000)-1_000_000, -int32(random.IntN(8)))
+		if b.IsZero() {
+			continue
+		}
+		scale := int32(random.IntN(20))
+
+		expected := roundRat(new(big.Rat).Quo(a.Rat(), b.Rat()), int64(scale))
+		actual := a.Quo(b, scale)
+		if !assert.Equal(t, expected.RatString(), actual.Rat().RatString(), "%s / %s, scale %d", a, b, scale) {
+			return
+		}
+	}
+}
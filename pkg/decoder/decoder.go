@@ -0,0 +1,180 @@
+package decoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/csv"
+	"github.com/jieggii/ecbratex/pkg/json"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownFormat error indicates that no registered decoder recognizes the data.
+var ErrUnknownFormat = errors.New("unknown rates data format")
+
+// Func decodes raw rates data to a new [xml.Data].
+type Func func(data []byte) (*xml.Data, error)
+
+// Decoder describes a single rates data format.
+type Decoder struct {
+	// Name is a short name of the format, for example "xml".
+	Name string
+
+	// ContentTypes are media types (without parameters) data of this format is served with.
+	ContentTypes []string
+
+	// Sniff reports whether data looks like data of this format.
+	// Data is passed with leading byte order mark and whitespace trimmed.
+	Sniff func(data []byte) bool
+
+	// Decode decodes data of this format.
+	Decode Func
+}
+
+// utf8BOM is the UTF-8 byte order mark.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+var (
+	// XML decodes the ECB XML (gesmes envelope) format.
+	XML = Decoder{
+		Name:         "xml",
+		ContentTypes: []string{"application/xml", "text/xml"},
+		Sniff: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("<"))
+		},
+		Decode: xml.NewData,
+	}
+
+	// Zip decodes the ECB zip archives containing a single CSV file.
+	Zip = Decoder{
+		Name:         "zip",
+		ContentTypes: []string{"application/zip", "application/x-zip-compressed"},
+		Sniff: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+		},
+		Decode: csv.NewDataFromZip,
+	}
+
+	// CSV decodes the ECB CSV format.
+	CSV = Decoder{
+		Name:         "csv",
+		ContentTypes: []string{"text/csv"},
+		Sniff: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("Date,"))
+		},
+		Decode: csv.NewData,
+	}
+
+	// JSON decodes the JSON format described by [json.Data].
+	JSON = Decoder{
+		Name:         "json",
+		ContentTypes: []string{"application/json"},
+		Sniff: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("{"))
+		},
+		Decode: json.NewData,
+	}
+)
+
+// Registry chooses a decoder for the given data by its content type or contents.
+type Registry struct {
+	mu       sync.RWMutex
+	decoders []Decoder
+	fallback *Decoder
+}
+
+// NewRegistry creates a new Registry containing the given decoders.
+func NewRegistry(decoders ...Decoder) *Registry {
+	r := &Registry{}
+	for _, d := range decoders {
+		r.Register(d)
+	}
+	return r
+}
+
+// Register adds decoder to the registry.
+// Decoders registered later take precedence over the ones registered earlier.
+func (r *Registry) Register(d Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decoders = append(r.decoders, d)
+}
+
+// SetFallback sets decoder used if no registered decoder recognizes the data.
+// Without fallback decoder, such data is rejected with ErrUnknownFormat.
+func (r *Registry) SetFallback(d Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = &d
+}
+
+// Lookup returns decoder for the given data and its content type.
+//
+// If contentType is not empty and matches one of the registered decoders, this decoder is returned.
+// Otherwise, the data is sniffed. Returns false if no decoder (including the fallback) is found.
+func (r *Registry) Lookup(data []byte, contentType string) (Decoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		for i := len(r.decoders) - 1; i >= 0; i-- {
+			for _, t := range r.decoders[i].ContentTypes {
+				if strings.EqualFold(t, mediaType) {
+					return r.decoders[i], true
+				}
+			}
+		}
+	}
+
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	for i := len(r.decoders) - 1; i >= 0; i-- {
+		if sniff := r.decoders[i].Sniff; sniff != nil && sniff(trimmed) {
+			return r.decoders[i], true
+		}
+	}
+
+	if r.fallback != nil {
+		return *r.fallback, true
+	}
+	return Decoder{}, false
+}
+
+// Decode decodes data using decoder chosen by Lookup.
+func (r *Registry) Decode(data []byte, contentType string) (*xml.Data, error) {
+	d, found := r.Lookup(data, contentType)
+	if !found {
+		return nil, ErrUnknownFormat
+	}
+
+	xmlData, err := d.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", d.Name, err)
+	}
+	return xmlData, nil
+}
+
+// Default is the registry used by the top-level functions.
+// It contains XML, Zip, CSV and JSON decoders and falls back to XML.
+var Default = newDefault()
+
+// newDefault creates the default registry.
+func newDefault() *Registry {
+	r := NewRegistry(XML, Zip, CSV, JSON)
+	r.SetFallback(XML)
+	return r
+}
+
+// Register adds decoder to the Default registry.
+func Register(d Decoder) {
+	Default.Register(d)
+}
+
+// Decode decodes data using the Default registry.
+func Decode(data []byte, contentType string) (*xml.Data, error) {
+	return Default.Decode(data, contentType)
+}
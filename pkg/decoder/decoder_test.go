@@ -0,0 +1,104 @@
+package decoder
+
+import (
+	"errors"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path"
+	"testing"
+)
+
+const testDataPath = "../../testdata"
+
+func readTestData(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(path.Join(testDataPath, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	var (
+		xmlData = readTestData(t, "eurofxref-daily.xml")
+		zipData = readTestData(t, "eurofxref.zip")
+	)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		contentType  string
+		expectedName string
+	}{
+		{"xml", xmlData, "", "xml"},
+		{"xml with BOM and whitespace", append([]byte("\xef\xbb\xbf \n"), xmlData...), "", "xml"},
+		{"zip", zipData, "", "zip"},
+		{"csv", []byte("Date, USD, \n27 February 2024, 1.0856, \n"), "", "csv"},
+		{"json", []byte(`{"records": []}`), "", "json"},
+		{"content type", []byte("whatever"), "application/json; charset=utf-8", "json"},
+		{"unknown content type", zipData, "application/octet-stream", "zip"},
+		{"fallback", []byte("some invalid data"), "", "xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, found := Default.Lookup(tt.data, tt.contentType)
+			if assert.True(t, found) {
+				assert.Equal(t, tt.expectedName, d.Name)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	custom := Decoder{
+		Name:         "custom",
+		ContentTypes: []string{"text/xml"},
+		Decode: func(data []byte) (*xml.Data, error) {
+			return &xml.Data{}, nil
+		},
+	}
+
+	r := NewRegistry(XML)
+	r.Register(custom)
+
+	d, found := r.Lookup([]byte("<xml/>"), "text/xml")
+	if assert.True(t, found) {
+		assert.Equal(t, "custom", d.Name)
+	}
+
+	d, found = r.Lookup([]byte("<xml/>"), "")
+	if assert.True(t, found) {
+		assert.Equal(t, "xml", d.Name)
+	}
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	t.Run("same data in different formats", func(t *testing.T) {
+		expected, err := xml.NewData(readTestData(t, "eurofxref-hist.xml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual, err := Decode(readTestData(t, "eurofxref-hist.zip"), "")
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected.Cubes, actual.Cubes)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		data, err := Decode([]byte("some invalid data"), "")
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Nil(t, data)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		data, err := NewRegistry(XML, JSON).Decode([]byte("some invalid data"), "")
+		assert.True(t, errors.Is(err, ErrUnknownFormat))
+		assert.Nil(t, data)
+	})
+}
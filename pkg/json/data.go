@@ -0,0 +1,50 @@
+package json
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"slices"
+	"strconv"
+)
+
+// Data is a struct representing a JSON document containing currency exchange rates records, for example:
+//
+//	{"records": [{"date": "2024-02-27", "rates": {"USD": 1.0856, "JPY": 163.04}}]}
+type Data struct {
+	Records []DataRecord `json:"records"`
+}
+
+// DataRecord is a struct representing a single JSON record containing rates of the given date.
+type DataRecord struct {
+	Date  string                 `json:"date"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// NewData decodes JSON bytes to a new [xml.Data].
+// Rates of each record are ordered by currency.
+func NewData(data []byte) (*xml.Data, error) {
+	jsonData := &Data{}
+	if err := json.Unmarshal(data, jsonData); err != nil {
+		return nil, err
+	}
+
+	xmlData := &xml.Data{Cubes: make([]xml.DataCube, 0, len(jsonData.Records))}
+	for _, rec := range jsonData.Records {
+		cube := xml.DataCube{Date: rec.Date, Rates: make([]xml.DataCubeRate, 0, len(rec.Rates))}
+		for currency, value := range rec.Rates {
+			rate, err := strconv.ParseFloat(value.String(), 32)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s rate: %w", currency, err)
+			}
+			cube.Rates = append(cube.Rates, xml.DataCubeRate{Currency: currency, Rate: float32(rate), Value: value.String()})
+		}
+		slices.SortFunc(cube.Rates, func(a, b xml.DataCubeRate) int {
+			return cmp.Compare(a.Currency, b.Currency)
+		})
+		xmlData.Cubes = append(xmlData.Cubes, cube)
+	}
+
+	return xmlData, nil
+}
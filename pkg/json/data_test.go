@@ -0,0 +1,38 @@
+package json
+
+import (
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewData(t *testing.T) {
+	t.Run("valid data", func(t *testing.T) {
+		data, err := NewData([]byte(`{"records": [
+			{"date": "2024-02-27", "rates": {"USD": 1.0856, "JPY": 163.04}},
+			{"date": "2024-02-26", "rates": {"USD": 1.0852}}
+		]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, &xml.Data{Cubes: []xml.DataCube{
+			{Date: "2024-02-27", Rates: []xml.DataCubeRate{
+				{Currency: "JPY", Rate: 163.04, Value: "163.04"},
+				{Currency: "USD", Rate: 1.0856, Value: "1.0856"},
+			}},
+			{Date: "2024-02-26", Rates: []xml.DataCubeRate{
+				{Currency: "USD", Rate: 1.0852, Value: "1.0852"},
+			}},
+		}}, data)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		data, err := NewData([]byte(`{"records": 1}`))
+		assert.Error(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("rate out of range", func(t *testing.T) {
+		data, err := NewData([]byte(`{"records": [{"date": "2024-02-27", "rates": {"USD": 1e50}}]}`))
+		assert.Error(t, err)
+		assert.Nil(t, data)
+	})
+}
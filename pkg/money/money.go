@@ -0,0 +1,194 @@
+// Package money provides Money type representing an amount of money in a particular currency.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch error indicates that operation was performed on amounts of different currencies.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrInvalidRatios error indicates that allocation ratios are negative or sum up to zero.
+	ErrInvalidRatios = errors.New("invalid allocation ratios")
+
+	// ErrOverflow error indicates that result of the operation does not fit into int64.
+	ErrOverflow = errors.New("amount overflows int64")
+)
+
+// Money is an amount of money in minor units of a currency, for example 1625 USD means 16.25 USD.
+// Minor units are defined by ISO 4217 exponent of the currency (see [currency.Info]).
+type Money struct {
+	amount   int64
+	currency currency.Currency
+}
+
+// New creates a new Money from amount in minor units of the given currency.
+func New(amount int64, c currency.Currency) Money {
+	return Money{amount: amount, currency: c}
+}
+
+// Amount returns amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns currency of the amount.
+func (m Money) Currency() currency.Currency {
+	return m.currency
+}
+
+// IsZero reports whether amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// Neg returns the amount with the opposite sign.
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Add returns m + other.
+// Returns ErrCurrencyMismatch if amounts are in different currencies.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("add %s to %s: %w", other.currency, m.currency, ErrCurrencyMismatch)
+	}
+
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: sum, currency: m.currency}, nil
+}
+
+// Sub returns m - other.
+// Returns ErrCurrencyMismatch if amounts are in different currencies.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("subtract %s from %s: %w", other.currency, m.currency, ErrCurrencyMismatch)
+	}
+
+	difference := m.amount - other.amount
+	if (other.amount > 0 && difference > m.amount) || (other.amount < 0 && difference < m.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: difference, currency: m.currency}, nil
+}
+
+// Allocate splits amount into parts proportional to the given ratios without losing any minor units:
+// the remainder is distributed one minor unit at a time starting from the first part.
+// For example, allocating 100 with ratios 1, 1, 1 gives 34, 33 and 33.
+// Returns ErrInvalidRatios if any ratio is negative or all ratios are zero.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	parts := make([]Money, len(ratios))
+	remainder := m.amount
+	for i, ratio := range ratios {
+		// share is truncated toward zero, so it never exceeds the amount:
+		share := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(ratio))
+		share.Quo(share, total)
+
+		parts[i] = Money{amount: share.Int64(), currency: m.currency}
+		remainder -= share.Int64()
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i++ {
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+		parts[i%len(ratios)].amount += step
+		remainder -= step
+	}
+
+	return parts, nil
+}
+
+// ConvertWith converts amount to the given currency using rates of the given record.
+// Conversion is performed using decimal arithmetic with rescaling between ISO 4217 exponents
+// of the currencies, see [record.Record.ConvertMinorsDecimal] and [record.WithCurrencyExponents].
+// Options can be used to change rounding mode of the result.
+func (m Money) ConvertWith(rec record.Record, to currency.Currency, opts ...record.ConvertOption) (Money, error) {
+	opts = append([]record.ConvertOption{record.WithCurrencyExponents()}, opts...)
+
+	amount, err := rec.ConvertMinorsDecimalCurrency(m.amount, m.currency, to, opts...)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: to}, nil
+}
+
+// exponent returns ISO 4217 exponent of the currency or zero if it is not known.
+func (m Money) exponent() int {
+	exponent, err := m.currency.Exponent()
+	if err != nil {
+		return 0
+	}
+	return exponent
+}
+
+// Decimal returns amount in major units, for example 16.25 for 1625 USD.
+// Amounts of unknown currencies are returned as is.
+func (m Money) Decimal() decimal.Decimal {
+	return decimal.New(m.amount, -int32(m.exponent()))
+}
+
+// String formats amount in major units with comma as the thousands separator and dot as the decimal separator
+// followed by the currency code, for example "1,234.56 USD" or "-1,234 JPY".
+// The format does not depend on locale. Amounts of unknown currencies are formatted as is.
+func (m Money) String() string {
+	digits := strconv.FormatUint(absUint64(m.amount), 10)
+
+	exponent := m.exponent()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-exponent], digits[len(digits)-exponent:]
+
+	var b strings.Builder
+	if m.amount < 0 {
+		b.WriteByte('-')
+	}
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if fracPart != "" {
+		b.WriteByte('.')
+		b.WriteString(fracPart)
+	}
+	b.WriteByte(' ')
+	b.WriteString(string(m.currency))
+	return b.String()
+}
+
+// absUint64 returns absolute value of x, which is correct for math.MinInt64 too.
+func absUint64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
@@ -0,0 +1,131 @@
+package money
+
+import (
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/rounding"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := New(1625, currency.USD).Add(New(-25, currency.USD))
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(1600, currency.USD), sum)
+	}
+
+	_, err = New(1625, currency.USD).Add(New(25, currency.EUR))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = New(math.MaxInt64, currency.USD).Add(New(1, currency.USD))
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestMoney_Sub(t *testing.T) {
+	difference, err := New(1625, currency.USD).Sub(New(1700, currency.USD))
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(-75, currency.USD), difference)
+	}
+
+	_, err = New(1625, currency.USD).Sub(New(25, currency.EUR))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = New(math.MinInt64, currency.USD).Sub(New(1, currency.USD))
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		ratios   []int64
+		expected []int64
+	}{
+		{"even split", 100, []int64{1, 1}, []int64{50, 50}},
+		{"remainder", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"negative remainder", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"weighted", 1000, []int64{70, 20, 10}, []int64{700, 200, 100}},
+		{"weighted remainder", 5, []int64{3, 7}, []int64{2, 3}},
+		{"zero ratio", 101, []int64{0, 1, 1}, []int64{0, 51, 50}},
+		{"large amount", math.MaxInt64, []int64{1, 1}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := New(tt.amount, currency.EUR).Allocate(tt.ratios...)
+			if assert.NoError(t, err) {
+				amounts := make([]int64, len(parts))
+				for i, part := range parts {
+					amounts[i] = part.Amount()
+					assert.Equal(t, currency.EUR, part.Currency())
+				}
+				assert.Equal(t, tt.expected, amounts)
+			}
+		})
+	}
+
+	t.Run("invalid ratios", func(t *testing.T) {
+		_, err := New(100, currency.EUR).Allocate()
+		assert.ErrorIs(t, err, ErrInvalidRatios)
+
+		_, err = New(100, currency.EUR).Allocate(0, 0)
+		assert.ErrorIs(t, err, ErrInvalidRatios)
+
+		_, err = New(100, currency.EUR).Allocate(1, -1)
+		assert.ErrorIs(t, err, ErrInvalidRatios)
+	})
+}
+
+func TestMoney_ConvertWith(t *testing.T) {
+	var rec = record.Record{"EUR": 1, "JPY": 3, "USD": 2}
+
+	converted, err := New(1000, currency.JPY).ConvertWith(rec, currency.EUR)
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(300000, currency.EUR), converted)
+	}
+
+	converted, err = New(1001, currency.EUR).ConvertWith(rec, currency.JPY)
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(3, currency.JPY), converted)
+	}
+
+	converted, err = New(1001, currency.EUR).ConvertWith(rec, currency.JPY, record.WithRounding(rounding.Ceil))
+	if assert.NoError(t, err) {
+		assert.Equal(t, New(4, currency.JPY), converted)
+	}
+
+	_, err = New(1000, currency.EUR).ConvertWith(rec, currency.GBP)
+	assert.ErrorIs(t, err, record.ErrRateNotFound)
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected string
+	}{
+		{New(123456, currency.USD), "1,234.56 USD"},
+		{New(-123456, currency.USD), "-1,234.56 USD"},
+		{New(5, currency.EUR), "0.05 EUR"},
+		{New(-5, currency.EUR), "-0.05 EUR"},
+		{New(0, currency.EUR), "0.00 EUR"},
+		{New(100000, currency.EUR), "1,000.00 EUR"},
+		{New(1234, currency.JPY), "1,234 JPY"},
+		{New(123, currency.JPY), "123 JPY"},
+		{New(1234567, currency.Currency("XXX")), "1,234,567 XXX"},
+		{New(math.MinInt64, currency.USD), "-92,233,720,368,547,758.08 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.money.String())
+		})
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "16.25", New(1625, currency.USD).Decimal().String())
+	assert.Equal(t, "1625", New(1625, currency.JPY).Decimal().String())
+	assert.True(t, New(0, currency.USD).IsZero())
+	assert.Equal(t, New(-1, currency.USD), New(1, currency.USD).Neg())
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is the default interval between retrievals of data
+// which is expected to be updated, but has not been updated yet.
+const DefaultRefreshInterval = 10 * time.Minute
+
+// CachingProvider is the Provider interface implementation which caches data
+// retrieved by another provider in memory.
+// Cached data stays valid until the next expected publication of reference rates by the ECB (see NextPublication).
+// If data retrieved after the expected publication is the same as the cached one (the ECB is late),
+// it is retrieved again every refresh interval until it changes or the next publication is expected.
+// Concurrent retrievals of the same data kind are collapsed into a single upstream retrieval.
+// Stale data served by the upstream provider (see StaleDataError) is passed through alongside with the error,
+// but is not cached, so that fresh data is retrieved again on the next request.
+// CachingProvider is safe for concurrent use.
+type CachingProvider struct {
+	upstream        Provider
+	now             func() time.Time
+	refreshInterval time.Duration
+
+	mu      sync.Mutex
+	entries map[DataKind]cachingEntry
+	flights flightGroup
+}
+
+// cachingEntry is data cached by CachingProvider.
+type cachingEntry struct {
+	data []byte
+
+	// fetchedAt is time when the data was retrieved for the first time.
+	fetchedAt time.Time
+
+	// expiresAt is time after which the data must be retrieved again.
+	expiresAt time.Time
+}
+
+// CachingProviderOption configures CachingProvider.
+type CachingProviderOption func(p *CachingProvider)
+
+// WithCacheClock sets function returning current time used by CachingProvider.
+// time.Now is used by default.
+func WithCacheClock(now func() time.Time) CachingProviderOption {
+	return func(p *CachingProvider) {
+		p.now = now
+	}
+}
+
+// WithRefreshInterval sets interval between retrievals of data which was expected to be updated,
+// but has not been updated yet. DefaultRefreshInterval is used by default.
+func WithRefreshInterval(interval time.Duration) CachingProviderOption {
+	return func(p *CachingProvider) {
+		p.refreshInterval = interval
+	}
+}
+
+// NewCachingProvider creates a new CachingProvider caching data retrieved by upstream.
+func NewCachingProvider(upstream Provider, opts ...CachingProviderOption) *CachingProvider {
+	p := &CachingProvider{
+		upstream:        upstream,
+		now:             time.Now,
+		refreshInterval: DefaultRefreshInterval,
+		entries:         make(map[DataKind]cachingEntry),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetRatesData returns cached data of the given kind or retrieves it using the upstream provider.
+func (p *CachingProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext returns cached data of the given kind or retrieves it using the upstream provider.
+// Waiting for the upstream retrieval is aborted as soon as ctx is done.
+// Returned data is shared between callers, so it must not be modified.
+func (p *CachingProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	if data, found := p.cached(kind); found {
+		return data, nil
+	}
+
+	return p.flights.do(ctx, kind, func(ctx context.Context) ([]byte, error) {
+		data, err := GetRatesDataContext(ctx, p.upstream, kind)
+		if err != nil {
+			var staleErr *StaleDataError
+			if errors.As(err, &staleErr) {
+				return data, err
+			}
+			return nil, err
+		}
+		p.store(kind, data)
+		return data, nil
+	})
+}
+
+// Invalidate removes cached data of the given kind, so that it is retrieved again on the next request.
+func (p *CachingProvider) Invalidate(kind DataKind) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.entries, kind)
+}
+
+// cached returns cached data of the given kind if it has not expired yet.
+func (p *CachingProvider) cached(kind DataKind) ([]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, found := p.entries[kind]
+	if !found || !p.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// store caches data of the given kind.
+func (p *CachingProvider) store(kind DataKind, data []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	entry := cachingEntry{
+		data:      data,
+		fetchedAt: now,
+		expiresAt: NextPublication(now),
+	}
+
+	// if data was expected to be updated, but it was not, retrieve it again soon:
+	previous, found := p.entries[kind]
+	if found && previous.fetchedAt.Before(PreviousPublication(now)) && bytes.Equal(previous.data, data) {
+		entry.fetchedAt = previous.fetchedAt
+		entry.expiresAt = now.Add(p.refreshInterval)
+		if nextPublication := NextPublication(now); entry.expiresAt.After(nextPublication) {
+			entry.expiresAt = nextPublication
+		}
+	}
+
+	p.entries[kind] = entry
+}
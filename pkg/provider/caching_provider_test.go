@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingProvider is a Provider which returns configured data and counts retrievals.
+type countingProvider struct {
+	mu    sync.Mutex
+	data  []byte
+	err   error
+	calls atomic.Int32
+
+	// release, if not nil, blocks retrievals until it is closed.
+	release chan struct{}
+}
+
+func (p *countingProvider) set(data []byte, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.data, p.err = data, err
+}
+
+func (p *countingProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+func (p *countingProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.data, p.err
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func TestNewCachingProvider(t *testing.T) {
+	upstream := &countingProvider{}
+
+	provider := NewCachingProvider(upstream)
+	assert.Same(t, upstream, provider.upstream)
+	assert.Equal(t, DefaultRefreshInterval, provider.refreshInterval)
+	assert.NotNil(t, provider.now)
+
+	provider = NewCachingProvider(upstream, WithRefreshInterval(time.Minute))
+	assert.Equal(t, time.Minute, provider.refreshInterval)
+}
+
+func TestCachingProvider_GetRatesData(t *testing.T) {
+	t.Run("data is cached until the next publication", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("monday")}
+			clock    = &fakeClock{now: time.Date(2024, 2, 26, 16, 0, 0, 0, time.UTC)} // Monday, after publication
+			provider = NewCachingProvider(upstream, WithCacheClock(clock.Now))
+		)
+
+		for range 3 {
+			data, err := provider.GetRatesData(DataKindLatest)
+			if assert.NoError(t, err) {
+				assert.Equal(t, []byte("monday"), data)
+			}
+		}
+		assert.EqualValues(t, 1, upstream.calls.Load())
+
+		// still before the next publication:
+		clock.Set(time.Date(2024, 2, 27, 14, 59, 0, 0, time.UTC))
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 1, upstream.calls.Load())
+
+		// other kinds are cached separately:
+		_, _ = provider.GetRatesData(DataKindTimeSeries)
+		assert.EqualValues(t, 2, upstream.calls.Load())
+
+		// after the next publication:
+		upstream.set([]byte("tuesday"), nil)
+		clock.Set(time.Date(2024, 2, 27, 15, 1, 0, 0, time.UTC))
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("tuesday"), data)
+		}
+		assert.EqualValues(t, 3, upstream.calls.Load())
+	})
+
+	t.Run("late publication", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("monday")}
+			clock    = &fakeClock{now: time.Date(2024, 2, 26, 16, 0, 0, 0, time.UTC)}
+			provider = NewCachingProvider(upstream, WithCacheClock(clock.Now), WithRefreshInterval(10*time.Minute))
+		)
+		_, _ = provider.GetRatesData(DataKindLatest)
+
+		// rates are expected to be published, but they are not:
+		clock.Set(time.Date(2024, 2, 27, 15, 1, 0, 0, time.UTC))
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 2, upstream.calls.Load())
+
+		// the same data is retrieved again after the refresh interval:
+		clock.Set(time.Date(2024, 2, 27, 15, 5, 0, 0, time.UTC))
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 2, upstream.calls.Load())
+
+		clock.Set(time.Date(2024, 2, 27, 15, 12, 0, 0, time.UTC))
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 3, upstream.calls.Load())
+
+		// rates are finally published:
+		upstream.set([]byte("tuesday"), nil)
+		clock.Set(time.Date(2024, 2, 27, 15, 23, 0, 0, time.UTC))
+		data, _ := provider.GetRatesData(DataKindLatest)
+		assert.Equal(t, []byte("tuesday"), data)
+		assert.EqualValues(t, 4, upstream.calls.Load())
+
+		// and are cached until the next publication:
+		clock.Set(time.Date(2024, 2, 27, 20, 0, 0, 0, time.UTC))
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 4, upstream.calls.Load())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{err: errors.New("upstream failed")}
+			provider = NewCachingProvider(upstream)
+		)
+
+		for range 2 {
+			data, err := provider.GetRatesData(DataKindLatest)
+			if assert.Error(t, err) {
+				assert.Empty(t, data)
+			}
+		}
+		assert.EqualValues(t, 2, upstream.calls.Load())
+	})
+
+	t.Run("stale data is passed through, but not cached", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{
+				data: []byte("stale data"),
+				err:  &StaleDataError{Kind: DataKindLatest, Err: errors.New("upstream failed")},
+			}
+			provider = NewCachingProvider(upstream)
+		)
+
+		for range 2 {
+			data, err := provider.GetRatesData(DataKindLatest)
+			var staleErr *StaleDataError
+			if assert.ErrorAs(t, err, &staleErr) {
+				assert.Equal(t, []byte("stale data"), data)
+			}
+		}
+		assert.EqualValues(t, 2, upstream.calls.Load())
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("data")}
+			provider = NewCachingProvider(upstream)
+		)
+
+		_, _ = provider.GetRatesData(DataKindLatest)
+		provider.Invalidate(DataKindLatest)
+		_, _ = provider.GetRatesData(DataKindLatest)
+		assert.EqualValues(t, 2, upstream.calls.Load())
+	})
+}
+
+func TestCachingProvider_GetRatesDataContext(t *testing.T) {
+	t.Run("concurrent requests are collapsed", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("data"), release: make(chan struct{})}
+			provider = NewCachingProvider(upstream)
+			wg       sync.WaitGroup
+		)
+
+		const callers = 10
+		results := make([][]byte, callers)
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = provider.GetRatesDataContext(context.Background(), DataKindTimeSeries)
+			}()
+		}
+
+		// let all callers join the in-flight retrieval:
+		assert.Eventually(t, func() bool {
+			provider.flights.mu.Lock()
+			defer provider.flights.mu.Unlock()
+			call, found := provider.flights.calls[DataKindTimeSeries]
+			return found && call.waiters == callers
+		}, time.Second, time.Millisecond)
+		close(upstream.release)
+		wg.Wait()
+
+		assert.EqualValues(t, 1, upstream.calls.Load())
+		for _, result := range results {
+			assert.Equal(t, []byte("data"), result)
+		}
+	})
+
+	t.Run("canceled caller does not cancel other callers", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("data"), release: make(chan struct{})}
+			provider = NewCachingProvider(upstream)
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		canceledErr := make(chan error)
+		go func() {
+			_, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+			canceledErr <- err
+		}()
+
+		result := make(chan []byte)
+		go func() {
+			data, _ := provider.GetRatesDataContext(context.Background(), DataKindLatest)
+			result <- data
+		}()
+
+		assert.Eventually(t, func() bool {
+			provider.flights.mu.Lock()
+			defer provider.flights.mu.Unlock()
+			call, found := provider.flights.calls[DataKindLatest]
+			return found && call.waiters == 2
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-canceledErr, context.Canceled)
+
+		close(upstream.release)
+		assert.Equal(t, []byte("data"), <-result)
+	})
+
+	t.Run("upstream retrieval is canceled when all callers are gone", func(t *testing.T) {
+		var (
+			upstream = &countingProvider{data: []byte("data"), release: make(chan struct{})}
+			provider = NewCachingProvider(upstream)
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.DeadlineExceeded) {
+			assert.Empty(t, data)
+		}
+
+		// the next caller starts a new retrieval:
+		close(upstream.release)
+		data, err = provider.GetRatesDataContext(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("data"), data)
+		}
+		assert.EqualValues(t, 2, upstream.calls.Load())
+	})
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoSources error indicates that ChainProvider has no sources to retrieve data from.
+var ErrNoSources = errors.New("no sources to retrieve data from")
+
+// ChainSource is a named provider used by ChainProvider.
+type ChainSource struct {
+	// Name is name of the source used in errors and to report which source served the data.
+	Name string
+
+	// Provider is the provider used to retrieve data.
+	Provider Provider
+}
+
+// ChainProvider is the Provider interface implementation which tries multiple sources in order
+// and returns data retrieved by the first successful one.
+// If a source serves stale data (see StaleDataError), the rest of the sources are still tried
+// and the stale data is returned only if all of them fail.
+type ChainProvider struct {
+	sources []ChainSource
+}
+
+// NewChainProvider creates a new ChainProvider trying the given sources in order.
+func NewChainProvider(sources ...ChainSource) *ChainProvider {
+	return &ChainProvider{sources: sources}
+}
+
+// GetRatesData returns data of the given kind retrieved by the first successful source.
+func (p *ChainProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext returns data of the given kind retrieved by the first successful source.
+// If all sources fail, errors of all of them are joined and returned.
+func (p *ChainProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	data, _, err := p.GetRatesDataSource(ctx, kind)
+	return data, err
+}
+
+// GetRatesDataSource is like GetRatesDataContext, but also returns name of the source which served the data.
+func (p *ChainProvider) GetRatesDataSource(ctx context.Context, kind DataKind) ([]byte, string, error) {
+	if len(p.sources) == 0 {
+		return nil, "", ErrNoSources
+	}
+
+	var (
+		errs       []error
+		staleData  []byte
+		staleName  string
+		staleFound bool
+	)
+	for _, source := range p.sources {
+		data, err := GetRatesDataContext(ctx, source.Provider, kind)
+		if err == nil {
+			return data, source.Name, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", source.Name, err))
+
+		var staleErr *StaleDataError
+		if !staleFound && errors.As(err, &staleErr) {
+			staleData, staleName, staleFound = data, source.Name, true
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	err := errors.Join(errs...)
+	if staleFound {
+		return staleData, staleName, err
+	}
+	return nil, "", err
+}
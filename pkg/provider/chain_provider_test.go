@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestChainProvider_GetRatesDataSource(t *testing.T) {
+	var (
+		errFirst  = errors.New("first failed")
+		errSecond = errors.New("second failed")
+	)
+
+	t.Run("first source succeeds", func(t *testing.T) {
+		var (
+			first    = &countingProvider{data: []byte("first")}
+			second   = &countingProvider{data: []byte("second")}
+			provider = NewChainProvider(ChainSource{"first", first}, ChainSource{"second", second})
+		)
+
+		data, source, err := provider.GetRatesDataSource(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("first"), data)
+			assert.Equal(t, "first", source)
+		}
+		assert.EqualValues(t, 0, second.calls.Load())
+	})
+
+	t.Run("falls back to the next source", func(t *testing.T) {
+		var (
+			first    = &countingProvider{err: errFirst}
+			second   = &countingProvider{data: []byte("second")}
+			provider = NewChainProvider(ChainSource{"first", first}, ChainSource{"second", second})
+		)
+
+		data, source, err := provider.GetRatesDataSource(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("second"), data)
+			assert.Equal(t, "second", source)
+		}
+	})
+
+	t.Run("all sources fail", func(t *testing.T) {
+		provider := NewChainProvider(
+			ChainSource{"first", &countingProvider{err: errFirst}},
+			ChainSource{"second", &countingProvider{err: errSecond}},
+		)
+
+		data, source, err := provider.GetRatesDataSource(context.Background(), DataKindLatest)
+		if assert.Error(t, err) {
+			assert.ErrorIs(t, err, errFirst)
+			assert.ErrorIs(t, err, errSecond)
+			assert.Contains(t, err.Error(), "first: first failed")
+			assert.Contains(t, err.Error(), "second: second failed")
+			assert.Empty(t, data)
+			assert.Empty(t, source)
+		}
+	})
+
+	t.Run("stale data is used as the last resort", func(t *testing.T) {
+		stale := &countingProvider{data: []byte("stale"), err: &StaleDataError{
+			Kind: DataKindLatest,
+			Age:  time.Hour,
+			Err:  errFirst,
+		}}
+
+		provider := NewChainProvider(
+			ChainSource{"stale", stale},
+			ChainSource{"fresh", &countingProvider{data: []byte("fresh")}},
+		)
+		data, source, err := provider.GetRatesDataSource(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("fresh"), data)
+			assert.Equal(t, "fresh", source)
+		}
+
+		provider = NewChainProvider(
+			ChainSource{"stale", stale},
+			ChainSource{"broken", &countingProvider{err: errSecond}},
+		)
+		data, source, err = provider.GetRatesDataSource(context.Background(), DataKindLatest)
+		var staleErr *StaleDataError
+		if assert.ErrorAs(t, err, &staleErr) {
+			assert.ErrorIs(t, err, errSecond)
+			assert.Equal(t, []byte("stale"), data)
+			assert.Equal(t, "stale", source)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		var (
+			second   = &countingProvider{data: []byte("second")}
+			provider = NewChainProvider(
+				ChainSource{"first", &countingProvider{data: []byte("first"), release: make(chan struct{})}},
+				ChainSource{"second", second},
+			)
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		data, _, err := provider.GetRatesDataSource(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, data)
+		}
+		assert.EqualValues(t, 0, second.calls.Load())
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		data, source, err := NewChainProvider().GetRatesDataSource(context.Background(), DataKindLatest)
+		if assert.ErrorIs(t, err, ErrNoSources) {
+			assert.Empty(t, data)
+			assert.Empty(t, source)
+		}
+	})
+}
+
+func TestChainProvider_GetRatesData(t *testing.T) {
+	provider := NewChainProvider(
+		ChainSource{"broken", &countingProvider{err: errors.New("failed")}},
+		ChainSource{"working", &countingProvider{data: []byte("data")}},
+	)
+
+	data, err := provider.GetRatesData(DataKindTimeSeries)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("data"), data)
+	}
+}
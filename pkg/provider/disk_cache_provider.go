@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StaleDataError is returned alongside with data cached on disk
+// when the upstream provider failed to retrieve fresh data.
+type StaleDataError struct {
+	// Kind is kind of the data.
+	Kind DataKind
+
+	// FetchedAt is time when the served data was retrieved from the upstream provider.
+	FetchedAt time.Time
+
+	// Age is age of the served data.
+	Age time.Duration
+
+	// Err is the error returned by the upstream provider.
+	Err error
+}
+
+// Error returns string representation of the error.
+func (e *StaleDataError) Error() string {
+	return fmt.Sprintf("serving stale %s data fetched %s ago: %v", e.Kind, e.Age.Round(time.Second), e.Err)
+}
+
+// Unwrap returns the error returned by the upstream provider.
+func (e *StaleDataError) Unwrap() error {
+	return e.Err
+}
+
+// diskCacheMetadata is metadata of data stored by DiskCacheProvider.
+type diskCacheMetadata struct {
+	FetchedAt    time.Time `json:"fetched_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Size         int       `json:"size"`
+	SHA256       string    `json:"sha256"`
+}
+
+// DiskCacheProvider is the Provider interface implementation which stores the last data
+// successfully retrieved by another provider in a directory and serves it if the upstream provider fails.
+// In such case the data is returned alongside with *StaleDataError, so callers can decide whether
+// stale data is acceptable.
+// Files are written atomically, so DiskCacheProvider is safe to use from multiple processes.
+type DiskCacheProvider struct {
+	upstream Provider
+	dir      string
+	files    *FSProvider
+
+	now          func() time.Time
+	maxStaleness time.Duration
+	onWriteError func(kind DataKind, err error)
+}
+
+// DiskCacheProviderOption configures DiskCacheProvider.
+type DiskCacheProviderOption func(p *DiskCacheProvider)
+
+// WithDiskCacheClock sets function returning current time used by DiskCacheProvider.
+// time.Now is used by default.
+func WithDiskCacheClock(now func() time.Time) DiskCacheProviderOption {
+	return func(p *DiskCacheProvider) {
+		p.now = now
+	}
+}
+
+// WithMaxStaleness sets the maximum age of data which is served if the upstream provider fails.
+// Zero value means no limit, which is the default.
+func WithMaxStaleness(maxStaleness time.Duration) DiskCacheProviderOption {
+	return func(p *DiskCacheProvider) {
+		p.maxStaleness = maxStaleness
+	}
+}
+
+// WithWriteErrorHandler sets function which is called if retrieved data could not be stored on disk.
+// Such errors are ignored by default.
+func WithWriteErrorHandler(handler func(kind DataKind, err error)) DiskCacheProviderOption {
+	return func(p *DiskCacheProvider) {
+		p.onWriteError = handler
+	}
+}
+
+// NewDiskCacheProvider creates a new DiskCacheProvider storing data retrieved by upstream in dir.
+// The directory is created on the first write if it does not exist.
+func NewDiskCacheProvider(upstream Provider, dir string, opts ...DiskCacheProviderOption) *DiskCacheProvider {
+	p := &DiskCacheProvider{
+		upstream: upstream,
+		dir:      dir,
+		files: NewFSProvider(
+			filepath.Join(dir, dataFilename(DataKindLatest)),
+			filepath.Join(dir, dataFilename(DataKindTimeSeries)),
+			filepath.Join(dir, dataFilename(DataKindTimeSeriesLast90Days)),
+		),
+
+		now:          time.Now,
+		onWriteError: func(DataKind, error) {},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetRatesData retrieves data of the given kind using the upstream provider (see GetRatesDataContext).
+func (p *DiskCacheProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext retrieves data of the given kind using the upstream provider and stores it on disk.
+// If the upstream provider fails, the data stored on disk is returned alongside with *StaleDataError.
+func (p *DiskCacheProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	data, err := GetRatesDataContext(ctx, p.upstream, kind)
+	if err == nil {
+		if err := p.write(kind, data); err != nil {
+			p.onWriteError(kind, err)
+		}
+		return data, nil
+	}
+
+	if errors.Is(err, ErrUnexpectedDataKind) || ctx.Err() != nil {
+		return nil, err
+	}
+
+	staleData, metadata, readErr := p.read(ctx, kind)
+	if readErr != nil {
+		return nil, err
+	}
+
+	age := p.now().Sub(metadata.FetchedAt)
+	if p.maxStaleness > 0 && age > p.maxStaleness {
+		return nil, err
+	}
+
+	return staleData, &StaleDataError{
+		Kind:      kind,
+		FetchedAt: metadata.FetchedAt,
+		Age:       age,
+		Err:       err,
+	}
+}
+
+// read reads data of the given kind and its metadata from disk and verifies its integrity.
+func (p *DiskCacheProvider) read(ctx context.Context, kind DataKind) ([]byte, diskCacheMetadata, error) {
+	var metadata diskCacheMetadata
+
+	rawMetadata, err := os.ReadFile(filepath.Join(p.dir, metadataFilename(kind)))
+	if err != nil {
+		return nil, metadata, err
+	}
+	if err := json.Unmarshal(rawMetadata, &metadata); err != nil {
+		return nil, metadata, fmt.Errorf("decode metadata: %w", err)
+	}
+
+	data, err := p.files.GetRatesDataContext(ctx, kind)
+	if err != nil {
+		return nil, metadata, err
+	}
+	if len(data) != metadata.Size || checksum(data) != metadata.SHA256 {
+		return nil, metadata, errors.New("data does not match its metadata")
+	}
+
+	return data, metadata, nil
+}
+
+// write stores data of the given kind and its metadata on disk.
+func (p *DiskCacheProvider) write(kind DataKind, data []byte) error {
+	metadata := diskCacheMetadata{
+		FetchedAt: p.now(),
+		Size:      len(data),
+		SHA256:    checksum(data),
+	}
+	if validatorsProvider, ok := p.upstream.(ValidatorsProvider); ok {
+		if validators, found := validatorsProvider.Validators(kind); found {
+			metadata.ETag = validators.ETag
+			metadata.LastModified = validators.LastModified
+		}
+	}
+
+	rawMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+
+	// data is written first, so that metadata never describes data which is not written yet.
+	// Data not matching its metadata is detected by checksum.
+	if err := writeFileAtomic(filepath.Join(p.dir, dataFilename(kind)), data); err != nil {
+		return fmt.Errorf("write data: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(p.dir, metadataFilename(kind)), rawMetadata); err != nil {
+		return fmt.Errorf("write metadata: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path,
+// so that readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// dataFilename returns name of the file storing data of the given kind.
+func dataFilename(kind DataKind) string {
+	return kind.String() + ".data"
+}
+
+// metadataFilename returns name of the file storing metadata of data of the given kind.
+func metadataFilename(kind DataKind) string {
+	return kind.String() + ".meta.json"
+}
+
+// checksum returns hex-encoded SHA-256 checksum of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/jieggii/ecbratex/tests"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewDiskCacheProvider(t *testing.T) {
+	upstream := &countingProvider{}
+
+	provider := NewDiskCacheProvider(upstream, "cache-dir", WithMaxStaleness(time.Hour))
+	assert.Same(t, upstream, provider.upstream)
+	assert.Equal(t, "cache-dir", provider.dir)
+	assert.Equal(t, time.Hour, provider.maxStaleness)
+	assert.Equal(t, filepath.Join("cache-dir", "latest.data"), provider.files.pathLatest)
+}
+
+func TestDiskCacheProvider_GetRatesData(t *testing.T) {
+	fetchedAt := time.Date(2024, 2, 27, 16, 0, 0, 0, time.UTC)
+
+	t.Run("fresh data is stored on disk", func(t *testing.T) {
+		var (
+			dir      = t.TempDir()
+			upstream = &countingProvider{data: []byte("fresh data")}
+			provider = NewDiskCacheProvider(upstream, dir, WithDiskCacheClock(func() time.Time { return fetchedAt }))
+		)
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("fresh data"), data)
+		}
+
+		storedData, err := os.ReadFile(filepath.Join(dir, "latest.data"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("fresh data"), storedData)
+		}
+
+		_, metadata, err := provider.read(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.True(t, fetchedAt.Equal(metadata.FetchedAt))
+			assert.Equal(t, len("fresh data"), metadata.Size)
+		}
+
+		// no temporary files are left:
+		entries, err := os.ReadDir(dir)
+		if assert.NoError(t, err) {
+			assert.Len(t, entries, 2)
+		}
+	})
+
+	t.Run("stale data is served if upstream fails", func(t *testing.T) {
+		var (
+			dir      = t.TempDir()
+			now      = fetchedAt
+			upstream = &countingProvider{data: []byte("data")}
+			provider = NewDiskCacheProvider(upstream, dir, WithDiskCacheClock(func() time.Time { return now }))
+		)
+		_, _ = provider.GetRatesData(DataKindTimeSeries)
+
+		upstreamErr := errors.New("upstream failed")
+		upstream.set(nil, upstreamErr)
+		now = fetchedAt.Add(3 * time.Hour)
+
+		data, err := provider.GetRatesData(DataKindTimeSeries)
+		assert.Equal(t, []byte("data"), data)
+
+		var staleErr *StaleDataError
+		if assert.ErrorAs(t, err, &staleErr) {
+			assert.Equal(t, DataKindTimeSeries, staleErr.Kind)
+			assert.Equal(t, 3*time.Hour, staleErr.Age)
+			assert.True(t, fetchedAt.Equal(staleErr.FetchedAt))
+		}
+		assert.ErrorIs(t, err, upstreamErr)
+	})
+
+	t.Run("stale data is too old", func(t *testing.T) {
+		var (
+			dir      = t.TempDir()
+			now      = fetchedAt
+			upstream = &countingProvider{data: []byte("data")}
+			provider = NewDiskCacheProvider(
+				upstream, dir,
+				WithDiskCacheClock(func() time.Time { return now }),
+				WithMaxStaleness(time.Hour),
+			)
+		)
+		_, _ = provider.GetRatesData(DataKindLatest)
+
+		upstream.set(nil, errors.New("upstream failed"))
+		now = fetchedAt.Add(2 * time.Hour)
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.Error(t, err) {
+			var staleErr *StaleDataError
+			assert.False(t, errors.As(err, &staleErr))
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("upstream fails and nothing is stored", func(t *testing.T) {
+		var (
+			upstreamErr = errors.New("upstream failed")
+			provider    = NewDiskCacheProvider(&countingProvider{err: upstreamErr}, t.TempDir())
+		)
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.Equal(t, upstreamErr, err) {
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("corrupted data is not served", func(t *testing.T) {
+		var (
+			dir         = t.TempDir()
+			upstream    = &countingProvider{data: []byte("data")}
+			upstreamErr = errors.New("upstream failed")
+			provider    = NewDiskCacheProvider(upstream, dir)
+		)
+		_, _ = provider.GetRatesData(DataKindLatest)
+
+		err := os.WriteFile(filepath.Join(dir, "latest.data"), []byte("dat@"), 0o644)
+		if err != nil {
+			panic(err)
+		}
+
+		upstream.set(nil, upstreamErr)
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.Equal(t, upstreamErr, err) {
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		// use a regular file as the cache directory:
+		dir := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(dir, nil, 0o644); err != nil {
+			panic(err)
+		}
+
+		var writeErr error
+		provider := NewDiskCacheProvider(
+			&countingProvider{data: []byte("data")}, dir,
+			WithWriteErrorHandler(func(kind DataKind, err error) {
+				writeErr = err
+			}),
+		)
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("data"), data)
+		}
+		assert.Error(t, writeErr)
+	})
+
+	t.Run("ETag of the upstream HTTPProvider is stored", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+
+		var (
+			upstream = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days, WithConditionalRequests())
+			provider = NewDiskCacheProvider(upstream, t.TempDir())
+		)
+
+		_, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			_, metadata, err := provider.read(context.Background(), DataKindLatest)
+			if assert.NoError(t, err) {
+				assert.NotEmpty(t, metadata.ETag)
+				assert.NotEmpty(t, metadata.LastModified)
+			}
+		}
+	})
+
+	t.Run("unexpected data kind", func(t *testing.T) {
+		provider := NewDiskCacheProvider(NewFSProvider("a", "b", "c"), t.TempDir())
+
+		data, err := provider.GetRatesData(DataKind(100))
+		if assert.ErrorIs(t, err, ErrUnexpectedDataKind) {
+			assert.Empty(t, data)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// The snapshot is refreshed from a local copy of the ECB time series file: put eurofxref-hist.xml
+// into the snapshot directory and run `go generate ./pkg/provider`. The generator rejects files
+// which do not contain the whole history of reference rates, see internal/gensnapshot.
+//go:generate go run ./internal/gensnapshot -in snapshot/eurofxref-hist.xml -out snapshot/eurofxref-hist.xml.gz
+
+//go:embed snapshot/eurofxref-hist.xml.gz
+var snapshot []byte
+
+// EmbeddedProvider is the Provider interface implementation which serves
+// a compressed snapshot of the time series file embedded into the binary.
+// Latest and last 90 days data are derived from the snapshot.
+// It is useful in environments without network or file system access.
+type EmbeddedProvider struct {
+	once sync.Once
+	data map[DataKind][]byte
+	err  error
+}
+
+// NewEmbeddedProvider creates a new EmbeddedProvider.
+func NewEmbeddedProvider() *EmbeddedProvider {
+	return &EmbeddedProvider{}
+}
+
+// GetRatesData returns snapshot data of the given kind.
+func (p *EmbeddedProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return p.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext returns snapshot data of the given kind.
+// The snapshot is decompressed on the first call.
+// Returned data is shared between callers, so it must not be modified.
+func (p *EmbeddedProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	switch kind {
+	case DataKindLatest, DataKindTimeSeries, DataKindTimeSeriesLast90Days:
+	default:
+		return nil, ErrUnexpectedDataKind
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.once.Do(p.load)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.data[kind], nil
+}
+
+// load decompresses the snapshot and derives latest and last 90 days data from it.
+func (p *EmbeddedProvider) load() {
+	reader, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if err != nil {
+		p.err = fmt.Errorf("decompress snapshot: %w", err)
+		return
+	}
+	timeSeries, err := io.ReadAll(reader)
+	if err != nil {
+		p.err = fmt.Errorf("decompress snapshot: %w", err)
+		return
+	}
+
+	latest, last90Days, err := splitTimeSeries(timeSeries)
+	if err != nil {
+		p.err = fmt.Errorf("split snapshot: %w", err)
+		return
+	}
+
+	p.data = map[DataKind][]byte{
+		DataKindLatest:               latest,
+		DataKindTimeSeries:           timeSeries,
+		DataKindTimeSeriesLast90Days: last90Days,
+	}
+}
+
+// splitTimeSeries derives latest and last 90 days documents from the time series document.
+// Derived documents keep the original envelope and contain the newest record
+// and records not older than 90 days before the newest record respectively.
+func splitTimeSeries(timeSeries []byte) ([]byte, []byte, error) {
+	const (
+		// depth of the Cube elements containing rates on a specific date:
+		// gesmes:Envelope > Cube > Cube.
+		recordDepth = 3
+		days        = 90
+	)
+
+	var (
+		decoder = xml.NewDecoder(bytes.NewReader(timeSeries))
+		depth   = 0
+
+		recordEnds  []int64          // offsets right after the end of each record
+		recordDates []time.Time      // dates of each record
+		tailStart   int64       = -1 // offset of the end of the element containing records
+	)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == recordDepth && element.Name.Local == "Cube" {
+				date, err := recordDate(element)
+				if err != nil {
+					return nil, nil, err
+				}
+				recordDates = append(recordDates, date)
+			}
+		case xml.EndElement:
+			if depth == recordDepth && element.Name.Local == "Cube" {
+				recordEnds = append(recordEnds, decoder.InputOffset())
+			}
+			if depth == recordDepth-1 && element.Name.Local == "Cube" {
+				tailStart = offset
+			}
+			depth--
+		}
+	}
+
+	if len(recordEnds) == 0 || tailStart == -1 {
+		return nil, nil, errors.New("document does not contain any rate records")
+	}
+
+	// records are stored in anti-chronological order:
+	oldest := recordDates[0].AddDate(0, 0, -days)
+	last90DaysCount := 0
+	for _, date := range recordDates {
+		if date.Before(oldest) {
+			break
+		}
+		last90DaysCount++
+	}
+
+	cut := func(count int) []byte {
+		head := timeSeries[:recordEnds[count-1]]
+		tail := timeSeries[tailStart:]
+		result := make([]byte, 0, len(head)+len(tail))
+		return append(append(result, head...), tail...)
+	}
+	return cut(1), cut(last90DaysCount), nil
+}
+
+// recordDate returns value of the time attribute of the record element.
+func recordDate(element xml.StartElement) (time.Time, error) {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == "time" {
+			return time.Parse(time.DateOnly, attr.Value)
+		}
+	}
+	return time.Time{}, errors.New("record does not have time attribute")
+}
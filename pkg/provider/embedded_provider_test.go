@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEmbeddedProvider_GetRatesData(t *testing.T) {
+	provider := NewEmbeddedProvider()
+
+	t.Run("time series", func(t *testing.T) {
+		data, err := provider.GetRatesData(DataKindTimeSeries)
+		if assert.NoError(t, err) {
+			xmlData, err := xml.NewData(data)
+			if assert.NoError(t, err) && assert.NotEmpty(t, xmlData.Cubes) {
+				// records are in anti-chronological order:
+				assert.Less(t, xmlData.Cubes[len(xmlData.Cubes)-1].Date, xmlData.Cubes[0].Date)
+			}
+		}
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			xmlData, err := xml.NewData(data)
+			if assert.NoError(t, err) && assert.Len(t, xmlData.Cubes, 1) {
+				assert.NotEmpty(t, xmlData.Cubes[0].Rates)
+			}
+		}
+	})
+
+	t.Run("last 90 days", func(t *testing.T) {
+		data, err := provider.GetRatesData(DataKindTimeSeriesLast90Days)
+		if assert.NoError(t, err) {
+			xmlData, err := xml.NewData(data)
+			if assert.NoError(t, err) {
+				assert.NotEmpty(t, xmlData.Cubes)
+			}
+		}
+	})
+
+	t.Run("unexpected data kind", func(t *testing.T) {
+		data, err := provider.GetRatesData(DataKind(100))
+		if assert.ErrorIs(t, err, ErrUnexpectedDataKind) {
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, data)
+		}
+	})
+}
+
+func TestSplitTimeSeries(t *testing.T) {
+	const (
+		head     = `<?xml version="1.0" encoding="UTF-8"?><gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref"><gesmes:subject>Reference rates</gesmes:subject><gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender><Cube>`
+		tail     = `</Cube></gesmes:Envelope>`
+		newest   = `<Cube time="2024-04-30"><Cube currency="USD" rate="1.0723"/></Cube>`
+		recent   = "\n" + `<Cube time="2024-02-01"><Cube currency="USD" rate="1.0837"/></Cube>`
+		old      = "\n" + `<Cube time="2024-01-30"><Cube currency="USD" rate="1.0837"/></Cube>`
+		document = head + newest + recent + old + tail
+	)
+
+	t.Run("valid document", func(t *testing.T) {
+		latest, last90Days, err := splitTimeSeries([]byte(document))
+		if assert.NoError(t, err) {
+			assert.Equal(t, head+newest+tail, string(latest))
+			assert.Equal(t, head+newest+recent+tail, string(last90Days))
+		}
+	})
+
+	t.Run("document without records", func(t *testing.T) {
+		_, _, err := splitTimeSeries([]byte(head + tail))
+		assert.Error(t, err)
+	})
+
+	t.Run("record without date", func(t *testing.T) {
+		_, _, err := splitTimeSeries([]byte(head + `<Cube><Cube currency="USD" rate="1"/></Cube>` + tail))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, _, err := splitTimeSeries([]byte("<Cube>"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// flightCall is an in-flight or completed flightGroup call.
+type flightCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	data []byte
+	err  error
+}
+
+// flightGroup collapses concurrent retrievals of the same data kind into a single one.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[DataKind]*flightCall
+}
+
+// do calls fn and returns its results, making sure that only one call for the given kind is in-flight at a time.
+// Duplicate callers wait for the in-flight call and receive the same results.
+// The context passed to fn is canceled only when all callers waiting for it are gone.
+func (g *flightGroup) do(ctx context.Context, kind DataKind, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[DataKind]*flightCall)
+	}
+	call, found := g.calls[kind]
+	if !found {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[kind] = call
+
+		go func() {
+			call.data, call.err = fn(callCtx)
+			g.forget(kind, call)
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// nobody is interested in the results anymore:
+			call.cancel()
+			g.forgetLocked(kind, call)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes the call from the group, so that subsequent callers start a new call.
+func (g *flightGroup) forget(kind DataKind, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.forgetLocked(kind, call)
+}
+
+// forgetLocked is like forget, but must be called with g.mu held.
+func (g *flightGroup) forgetLocked(kind DataKind, call *flightCall) {
+	if g.calls[kind] == call {
+		delete(g.calls, kind)
+	}
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"os"
 )
 
@@ -23,6 +24,12 @@ func NewFSProvider(pathLatest string, pathTimeSeries string, pathTimeSeriesLast9
 
 // GetRatesData reads a file corresponding to the given data kind and returns its content.
 func (f FSProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return f.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext reads a file corresponding to the given data kind and returns its content.
+// Reading is aborted as soon as ctx is done.
+func (f FSProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
 	var path string
 	switch kind {
 	case DataKindLatest:
@@ -35,7 +42,17 @@ func (f FSProvider) GetRatesData(kind DataKind) ([]byte, error) {
 		return nil, ErrUnexpectedDataKind
 	}
 
-	data, err := os.ReadFile(path)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := readAll(ctx, file)
 	if err != nil {
 		return nil, err
 	}
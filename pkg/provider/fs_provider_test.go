@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"path"
 	"testing"
@@ -75,3 +76,28 @@ func TestFSProvider_GetRatesData(t *testing.T) {
 		}
 	})
 }
+
+func TestFSProvider_GetRatesDataContext(t *testing.T) {
+	provider := NewFSProvider(
+		path.Join(testDataPath, "eurofxref-daily.xml"),
+		path.Join(testDataPath, "eurofxref-hist.xml"),
+		path.Join(testDataPath, "eurofxref-hist-90d.xml"),
+	)
+
+	t.Run("active context", func(t *testing.T) {
+		data, err := provider.GetRatesDataContext(context.Background(), DataKindTimeSeries)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindTimeSeries)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, data)
+		}
+	})
+}
@@ -1,30 +1,130 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 )
 
+// ErrBodyTooLarge error indicates that response body exceeds the maximum allowed size.
+var ErrBodyTooLarge = errors.New("response body is too large")
+
 // HTTPProvider is the Provider interface implementation which uses
 // HTTP GET request to retrieve currency exchange rate records.
 type HTTPProvider struct {
 	urlLatest               string
 	urlTimeSeries           string
 	urlTimeSeriesLast90days string
+
+	client      *http.Client
+	header      http.Header
+	timeout     time.Duration
+	maxBodySize int64
+	retryPolicy RetryPolicy
+
+	conditional  bool
+	mu           sync.Mutex
+	cache        map[DataKind]conditionalEntry
+	contentTypes map[DataKind]string
+}
+
+// conditionalEntry is the last successfully fetched data of some kind alongside with its validators.
+type conditionalEntry struct {
+	data       []byte
+	validators Validators
+}
+
+// HTTPProviderOption configures HTTPProvider.
+type HTTPProviderOption func(p *HTTPProvider)
+
+// WithClient sets HTTP client used to perform requests.
+// http.DefaultClient is used by default or if client is nil.
+func WithClient(client *http.Client) HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// WithUserAgent sets value of the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) HTTPProviderOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader sets header sent with every request.
+func WithHeader(key string, value string) HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		p.header.Set(key, value)
+	}
+}
+
+// WithTimeout sets time limit for a single request attempt, including reading of the response body.
+// Zero timeout means no limit, which is the default.
+func WithTimeout(timeout time.Duration) HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		p.timeout = timeout
+	}
+}
+
+// WithMaxBodySize sets the maximum allowed size of the response body in bytes.
+// Responses exceeding the limit are rejected with ErrBodyTooLarge.
+// Zero size means no limit, which is the default.
+func WithMaxBodySize(size int64) HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		p.maxBodySize = size
+	}
+}
+
+// WithConditionalRequests enables conditional requests.
+// HTTPProvider remembers the last fetched data and its ETag and Last-Modified validators for each data kind,
+// sends If-None-Match and If-Modified-Since headers and reuses the remembered data
+// if the server responds with 304 Not Modified.
+// Note that the remembered data is kept in memory and is shared between callers, so it must not be modified.
+func WithConditionalRequests() HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		p.conditional = true
+	}
 }
 
 // NewHTTPProvider creates a new HTTPProvider.
-func NewHTTPProvider(urlLatest string, urlTimeSeries string, urlTimeSeriesLast90Days string) *HTTPProvider {
-	return &HTTPProvider{
+func NewHTTPProvider(urlLatest string, urlTimeSeries string, urlTimeSeriesLast90Days string, opts ...HTTPProviderOption) *HTTPProvider {
+	p := &HTTPProvider{
 		urlLatest:               urlLatest,
 		urlTimeSeries:           urlTimeSeries,
 		urlTimeSeriesLast90days: urlTimeSeriesLast90Days,
+
+		client:       http.DefaultClient,
+		header:       make(http.Header),
+		cache:        make(map[DataKind]conditionalEntry),
+		contentTypes: make(map[DataKind]string),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
 // GetRatesData fetches exchange rates records file by its URL corresponding to the given data kind.
 func (f *HTTPProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	return f.GetRatesDataContext(context.Background(), kind)
+}
+
+// GetRatesDataContext fetches exchange rates records file by its URL corresponding to the given data kind.
+// The request, including reading of the response body, is aborted as soon as ctx is done.
+func (f *HTTPProvider) GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error) {
+	data, _, err := f.GetRatesDataConditional(ctx, kind)
+	return data, err
+}
+
+// GetRatesDataConditional is like GetRatesDataContext, but also reports whether data was modified
+// since the previous call, so that callers can skip decoding of the same data.
+// Data is always reported as modified unless conditional requests are enabled using WithConditionalRequests.
+func (f *HTTPProvider) GetRatesDataConditional(ctx context.Context, kind DataKind) ([]byte, bool, error) {
 	// choose URL:
 	var url string
 	switch kind {
@@ -35,22 +135,169 @@ func (f *HTTPProvider) GetRatesData(kind DataKind) ([]byte, error) {
 	case DataKindTimeSeriesLast90Days:
 		url = f.urlTimeSeriesLast90days
 	default:
-		return nil, ErrUnexpectedDataKind
+		return nil, false, ErrUnexpectedDataKind
 	}
 
-	resp, err := http.Get(url)
+	entry := f.cachedEntry(kind)
+	for attempt := 1; ; attempt++ {
+		data, resp, err := f.get(ctx, url, entry)
+		if err == nil {
+			if resp.StatusCode == http.StatusNotModified {
+				return entry.data, false, nil
+			}
+			f.storeEntry(kind, resp, data)
+			f.storeContentType(kind, resp)
+			return data, true, nil
+		}
+
+		if ctx.Err() != nil || !f.retryPolicy.retryable(attempt, resp, err) {
+			return nil, false, err
+		}
+
+		if err := sleep(ctx, f.retryPolicy.delay(attempt, resp)); err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// get performs a single GET request to the given URL and reads the response body.
+// If entry is not nil, the request is conditional and 304 Not Modified response is considered successful.
+// Returns the response (with its body closed) alongside with an error if it was received.
+func (f *HTTPProvider) get(ctx context.Context, url string, entry *conditionalEntry) ([]byte, *http.Response, error) {
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	for key, values := range f.header {
+		req.Header[key] = values
+	}
+	if entry != nil {
+		if entry.validators.ETag != "" {
+			req.Header.Set("If-None-Match", entry.validators.ETag)
+		}
+		if entry.validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.validators.LastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, resp, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GET %s: unexpected HTTP status %d", url, resp.StatusCode)
+		return nil, resp, fmt.Errorf("GET %s: unexpected HTTP status %d", url, resp.StatusCode)
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	data, err := f.readBody(ctx, resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, resp, fmt.Errorf("GET %s: read body: %w", url, err)
+	}
+
+	return data, resp, nil
+}
+
+// readBody reads response body respecting the maximum body size.
+func (f *HTTPProvider) readBody(ctx context.Context, body io.Reader) ([]byte, error) {
+	if f.maxBodySize <= 0 {
+		return readAll(ctx, body)
 	}
 
+	// read one extra byte to find out whether the limit is exceeded:
+	data, err := readAll(ctx, io.LimitReader(body, f.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
 	return data, nil
 }
+
+// Validators returns validators of the last successfully fetched data of the given kind
+// and a boolean indicating whether they are known.
+// Validators are remembered only if conditional requests are enabled using WithConditionalRequests.
+func (f *HTTPProvider) Validators(kind DataKind) (Validators, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, found := f.cache[kind]
+	if !found {
+		return Validators{}, false
+	}
+	return entry.validators, true
+}
+
+// ContentType returns value of the Content-Type header of the last successfully fetched data of the given kind
+// and a boolean indicating whether the header was received.
+func (f *HTTPProvider) ContentType(kind DataKind) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	contentType, found := f.contentTypes[kind]
+	return contentType, found
+}
+
+// cachedEntry returns the remembered entry of the given data kind
+// or nil if conditional requests are disabled or nothing is remembered yet.
+func (f *HTTPProvider) cachedEntry(kind DataKind) *conditionalEntry {
+	if !f.conditional {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, found := f.cache[kind]
+	if !found {
+		return nil
+	}
+	return &entry
+}
+
+// storeEntry remembers data of the given kind alongside with validators of the response it was received with.
+func (f *HTTPProvider) storeEntry(kind DataKind, resp *http.Response, data []byte) {
+	if !f.conditional {
+		return
+	}
+
+	entry := conditionalEntry{
+		data: data,
+		validators: Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry.validators.ETag == "" && entry.validators.LastModified == "" {
+		delete(f.cache, kind)
+		return
+	}
+	f.cache[kind] = entry
+}
+
+// storeContentType remembers value of the Content-Type header of the response data of the given kind was received with.
+func (f *HTTPProvider) storeContentType(kind DataKind, resp *http.Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		f.contentTypes[kind] = contentType
+	} else {
+		delete(f.contentTypes, kind)
+	}
+}
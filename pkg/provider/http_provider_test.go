@@ -1,9 +1,14 @@
 package provider
 
 import (
+	"context"
 	"github.com/jieggii/ecbratex/tests"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const testDataPath = "./../../testdata"
@@ -19,6 +24,33 @@ func TestNewHTTPProvider(t *testing.T) {
 	assert.Equal(t, urlLatest, provider.urlLatest)
 	assert.Equal(t, urlTimeSeries, provider.urlTimeSeries)
 	assert.Equal(t, urlTimeSeriesLast90Days, provider.urlTimeSeriesLast90days)
+	assert.Equal(t, http.DefaultClient, provider.client)
+	assert.Empty(t, provider.header)
+	assert.Zero(t, provider.timeout)
+	assert.Zero(t, provider.maxBodySize)
+
+	t.Run("with options", func(t *testing.T) {
+		client := &http.Client{}
+
+		provider := NewHTTPProvider(
+			urlLatest, urlTimeSeries, urlTimeSeriesLast90Days,
+			WithClient(client),
+			WithUserAgent("ecbratex-test"),
+			WithHeader("X-Test", "value"),
+			WithTimeout(time.Minute),
+			WithMaxBodySize(1024),
+		)
+		assert.Same(t, client, provider.client)
+		assert.Equal(t, "ecbratex-test", provider.header.Get("User-Agent"))
+		assert.Equal(t, "value", provider.header.Get("X-Test"))
+		assert.Equal(t, time.Minute, provider.timeout)
+		assert.Equal(t, int64(1024), provider.maxBodySize)
+	})
+
+	t.Run("nil client", func(t *testing.T) {
+		provider := NewHTTPProvider(urlLatest, urlTimeSeries, urlTimeSeriesLast90Days, WithClient(nil))
+		assert.Equal(t, http.DefaultClient, provider.client)
+	})
 }
 
 func TestHTTPProvider_GetRatesData(t *testing.T) {
@@ -79,3 +111,325 @@ func TestHTTPProvider_GetRatesData(t *testing.T) {
 	})
 
 }
+
+func TestHTTPProvider_GetRatesDataContext(t *testing.T) {
+	t.Run("active context", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+		)
+		defer server.Close()
+
+		data, err := provider.GetRatesDataContext(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+		)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.Canceled) {
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("deadline exceeded while reading body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<?xml"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done() // never finish the body
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.URL, server.URL, server.URL)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.DeadlineExceeded) {
+			assert.Empty(t, data)
+		}
+	})
+}
+
+// closeTrackingTransport is an http.RoundTripper which records whether response bodies were closed.
+type closeTrackingTransport struct {
+	closed int
+}
+
+type closeTrackingBody struct {
+	io.ReadCloser
+	transport *closeTrackingTransport
+}
+
+func (b closeTrackingBody) Close() error {
+	b.transport.closed++
+	return b.ReadCloser.Close()
+}
+
+func (t *closeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = closeTrackingBody{ReadCloser: resp.Body, transport: t}
+	return resp, nil
+}
+
+func TestHTTPProvider_Options(t *testing.T) {
+	t.Run("headers are sent", func(t *testing.T) {
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(
+			server.URL, server.URL, server.URL,
+			WithUserAgent("ecbratex-test"),
+			WithHeader("X-Test", "value"),
+		)
+
+		_, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "ecbratex-test", header.Get("User-Agent"))
+			assert.Equal(t, "value", header.Get("X-Test"))
+		}
+	})
+
+	t.Run("custom client is used and response bodies are closed", func(t *testing.T) {
+		var (
+			server    = tests.NewTestHTTPServer(testDataPath, false)
+			transport = &closeTrackingTransport{}
+			provider  = NewHTTPProvider(
+				server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+				WithClient(&http.Client{Transport: transport}),
+			)
+		)
+		defer server.Close()
+
+		_, err := provider.GetRatesData(DataKindLatest)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, transport.closed)
+
+		brokenServer := tests.NewTestHTTPServer(testDataPath, true)
+		defer brokenServer.Close()
+
+		provider = NewHTTPProvider(
+			brokenServer.URLLatest, brokenServer.URLTimeSeries, brokenServer.URLTimeSeriesLast90Days,
+			WithClient(&http.Client{Transport: transport}),
+		)
+		_, err = provider.GetRatesData(DataKindLatest)
+		assert.Error(t, err)
+		assert.Equal(t, 2, transport.closed)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.URL, server.URL, server.URL, WithTimeout(50*time.Millisecond))
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.ErrorIs(t, err, context.DeadlineExceeded) {
+			assert.Empty(t, data)
+		}
+	})
+
+	t.Run("max body size", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithMaxBodySize(16),
+		)
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.ErrorIs(t, err, ErrBodyTooLarge) {
+			assert.Empty(t, data)
+		}
+
+		provider = NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithMaxBodySize(1<<20),
+		)
+		data, err = provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+		}
+	})
+}
+
+func TestHTTPProvider_GetRatesDataConditional(t *testing.T) {
+	t.Run("ETag", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(
+				server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+				WithConditionalRequests(),
+			)
+		)
+		defer server.Close()
+
+		data, modified, err := provider.GetRatesDataConditional(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+			assert.True(t, modified)
+		}
+
+		cachedData, modified, err := provider.GetRatesDataConditional(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, data, cachedData)
+			assert.False(t, modified)
+		}
+
+		// other data kinds are remembered separately:
+		data, modified, err = provider.GetRatesDataConditional(context.Background(), DataKindTimeSeries)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+			assert.True(t, modified)
+		}
+
+		assert.Equal(t, 3, server.Requests())
+	})
+
+	t.Run("Last-Modified", func(t *testing.T) {
+		const lastModified = "Tue, 27 Feb 2024 15:00:00 GMT"
+		var requestHeaders []http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestHeaders = append(requestHeaders, r.Header)
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", lastModified)
+			_, _ = w.Write([]byte("data"))
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.URL, server.URL, server.URL, WithConditionalRequests())
+
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("data"), data)
+		}
+
+		data, modified, err := provider.GetRatesDataConditional(context.Background(), DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("data"), data)
+			assert.False(t, modified)
+		}
+
+		if assert.Len(t, requestHeaders, 2) {
+			assert.Empty(t, requestHeaders[0].Get("If-Modified-Since"))
+			assert.Equal(t, lastModified, requestHeaders[1].Get("If-Modified-Since"))
+			assert.Empty(t, requestHeaders[1].Get("If-None-Match"))
+		}
+	})
+
+	t.Run("unexpected 304 Not Modified", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		}))
+		defer server.Close()
+
+		provider := NewHTTPProvider(server.URL, server.URL, server.URL, WithConditionalRequests())
+
+		data, modified, err := provider.GetRatesDataConditional(context.Background(), DataKindLatest)
+		if assert.Error(t, err) {
+			assert.Empty(t, data)
+			assert.False(t, modified)
+		}
+	})
+
+	t.Run("conditional requests disabled", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+		)
+		defer server.Close()
+
+		for range 2 {
+			data, modified, err := provider.GetRatesDataConditional(context.Background(), DataKindLatest)
+			if assert.NoError(t, err) {
+				assert.NotEmpty(t, data)
+				assert.True(t, modified)
+			}
+		}
+		assert.Empty(t, provider.cache)
+	})
+}
+
+func TestHTTPProvider_Validators(t *testing.T) {
+	t.Run("conditional requests enabled", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days, WithConditionalRequests())
+		)
+		defer server.Close()
+
+		validators, found := provider.Validators(DataKindLatest)
+		if assert.False(t, found) {
+			assert.Zero(t, validators)
+		}
+
+		_, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			validators, found := provider.Validators(DataKindLatest)
+			if assert.True(t, found) {
+				assert.NotEmpty(t, validators.ETag)
+				assert.NotEmpty(t, validators.LastModified)
+			}
+		}
+	})
+
+	t.Run("conditional requests disabled", func(t *testing.T) {
+		var (
+			server   = tests.NewTestHTTPServer(testDataPath, false)
+			provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+		)
+		defer server.Close()
+
+		_, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			validators, found := provider.Validators(DataKindLatest)
+			if assert.False(t, found) {
+				assert.Zero(t, validators)
+			}
+		}
+	})
+}
+
+func TestHTTPProvider_ContentType(t *testing.T) {
+	var (
+		server   = tests.NewTestHTTPServer(testDataPath, false)
+		provider = NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+	)
+	defer server.Close()
+
+	contentType, found := provider.ContentType(DataKindLatest)
+	if assert.False(t, found) {
+		assert.Empty(t, contentType)
+	}
+
+	_, err := provider.GetRatesData(DataKindLatest)
+	if assert.NoError(t, err) {
+		contentType, found := provider.ContentType(DataKindLatest)
+		if assert.True(t, found) {
+			assert.Equal(t, "text/xml; charset=utf-8", contentType)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Gensnapshot compresses the ECB time series file (eurofxref-hist.xml)
+into the snapshot embedded by provider.EmbeddedProvider.
+
+Usage:
+
+	go run ./internal/gensnapshot -in path/to/eurofxref-hist.xml -out snapshot/eurofxref-hist.xml.gz
+	go run ./internal/gensnapshot -url https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml -out snapshot/eurofxref-hist.xml.gz
+
+The input file is either read from the given path, which works without network access,
+or downloaded from the given URL.
+It is validated before the snapshot is written: it must contain the whole history
+of reference rates starting from the first publication on 1999-01-04.
+The output is deterministic: the same input always produces the same snapshot.
+*/
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// firstPublication is date of the first reference rates published by the ECB.
+const firstPublication = "1999-01-04"
+
+func main() {
+	var (
+		in  = flag.String("in", "", "path to the eurofxref-hist.xml file")
+		url = flag.String("url", "", "URL to download the eurofxref-hist.xml file from")
+		out = flag.String("out", "", "path to the snapshot file to write")
+	)
+	flag.Parse()
+
+	if (*in == "") == (*url == "") || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*in, *url, *out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(in string, url string, out string) error {
+	var (
+		data   []byte
+		source string
+		err    error
+	)
+	if url != "" {
+		data, err = download(url)
+		source = url
+	} else {
+		data, err = os.ReadFile(in)
+		source = in
+	}
+	if err != nil {
+		return err
+	}
+
+	xmlData, err := validate(data)
+	if err != nil {
+		return fmt.Errorf("validate %s: %w", source, err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("snapshot %s written: %d records, newest from %s", out, len(xmlData.Cubes), xmlData.Cubes[0].Date)
+	return nil
+}
+
+// validate decodes the time series document and checks that it contains the whole history.
+func validate(data []byte) (*xml.Data, error) {
+	xmlData, err := xml.NewData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(xmlData.Cubes) == 0 {
+		return nil, errors.New("document does not contain any rate records")
+	}
+	if oldest := xmlData.Cubes[len(xmlData.Cubes)-1].Date; oldest != firstPublication {
+		return nil, fmt.Errorf("document is not the whole history: the oldest record is from %s instead of %s", oldest, firstPublication)
+	}
+	return xmlData, nil
+}
+
+// download downloads the file from the given URL.
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: time.Minute}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: unexpected status %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
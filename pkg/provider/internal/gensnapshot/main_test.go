@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	const (
+		head = `<?xml version="1.0" encoding="UTF-8"?><gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref"><gesmes:subject>Reference rates</gesmes:subject><gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender><Cube>`
+		tail = `</Cube></gesmes:Envelope>`
+	)
+
+	t.Run("whole history", func(t *testing.T) {
+		xmlData, err := validate([]byte(head +
+			`<Cube time="1999-01-05"><Cube currency="USD" rate="1.1790"/></Cube>` +
+			`<Cube time="1999-01-04"><Cube currency="USD" rate="1.1789"/></Cube>` +
+			tail))
+		if assert.NoError(t, err) {
+			assert.Len(t, xmlData.Cubes, 2)
+		}
+	})
+
+	t.Run("partial history", func(t *testing.T) {
+		_, err := validate([]byte(head + `<Cube time="2023-11-30"><Cube currency="USD" rate="1.0940"/></Cube>` + tail))
+		assert.ErrorContains(t, err, "not the whole history")
+	})
+
+	t.Run("document without records", func(t *testing.T) {
+		_, err := validate([]byte(head + tail))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := validate([]byte("<Cube>"))
+		assert.Error(t, err)
+	})
+}
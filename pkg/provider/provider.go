@@ -1,6 +1,11 @@
 package provider
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
 
 var ErrUnexpectedDataKind = errors.New("unexpected data kind")
 
@@ -12,7 +17,86 @@ const (
 	DataKindTimeSeriesLast90Days
 )
 
+// String returns name of the data kind.
+func (k DataKind) String() string {
+	switch k {
+	case DataKindLatest:
+		return "latest"
+	case DataKindTimeSeries:
+		return "time-series"
+	case DataKindTimeSeriesLast90Days:
+		return "time-series-last-90-days"
+	default:
+		return fmt.Sprintf("DataKind(%d)", k)
+	}
+}
+
 // Provider is an interface that defines behavior for fetching currency exchange rate data.
 type Provider interface {
+	// GetRatesData returns raw rates data of the given kind.
 	GetRatesData(kind DataKind) ([]byte, error)
 }
+
+// ContextProvider is implemented by providers which support cancellation of data retrieval.
+// All providers of this package implement it.
+type ContextProvider interface {
+	Provider
+
+	// GetRatesDataContext returns raw rates data of the given kind.
+	// Retrieval is aborted as soon as ctx is done.
+	GetRatesDataContext(ctx context.Context, kind DataKind) ([]byte, error)
+}
+
+// GetRatesDataContext returns raw rates data of the given kind retrieved by p.
+// If p does not implement ContextProvider, it falls back to GetRatesData, so ctx is only checked before retrieval.
+func GetRatesDataContext(ctx context.Context, p Provider, kind DataKind) ([]byte, error) {
+	if cp, ok := p.(ContextProvider); ok {
+		return cp.GetRatesDataContext(ctx, kind)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.GetRatesData(kind)
+}
+
+// Validators are HTTP validators of the retrieved data.
+type Validators struct {
+	// ETag is value of the ETag header.
+	ETag string
+
+	// LastModified is value of the Last-Modified header.
+	LastModified string
+}
+
+// ValidatorsProvider is implemented by providers which know validators of the last retrieved data.
+type ValidatorsProvider interface {
+	// Validators returns validators of the last retrieved data of the given kind
+	// and a boolean indicating whether data of this kind was retrieved.
+	Validators(kind DataKind) (Validators, bool)
+}
+
+// ContentTypeProvider is implemented by providers which know media type of the last retrieved data.
+type ContentTypeProvider interface {
+	// ContentType returns media type of the last retrieved data of the given kind
+	// and a boolean indicating whether it is known.
+	ContentType(kind DataKind) (string, bool)
+}
+
+// contextReader is an io.Reader which stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// readAll reads from r until EOF or until ctx is done.
+func readAll(ctx context.Context, r io.Reader) ([]byte, error) {
+	return io.ReadAll(contextReader{ctx: ctx, r: r})
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"path"
+	"testing"
+)
+
+func TestDataKind_String(t *testing.T) {
+	cases := map[DataKind]string{
+		DataKindLatest:               "latest",
+		DataKindTimeSeries:           "time-series",
+		DataKindTimeSeriesLast90Days: "time-series-last-90-days",
+		DataKind(100):                "DataKind(100)",
+	}
+
+	for kind, name := range cases {
+		assert.Equal(t, name, kind.String())
+	}
+}
+
+// plainProvider implements only the Provider interface.
+type plainProvider struct {
+	calls int
+}
+
+func (p *plainProvider) GetRatesData(kind DataKind) ([]byte, error) {
+	p.calls++
+	return []byte(kind.String()), nil
+}
+
+func TestGetRatesDataContext(t *testing.T) {
+	t.Run("context provider", func(t *testing.T) {
+		p := NewFSProvider(
+			path.Join(testDataPath, "eurofxref-daily.xml"),
+			path.Join(testDataPath, "eurofxref-hist.xml"),
+			path.Join(testDataPath, "eurofxref-hist-90d.xml"),
+		)
+
+		data, err := GetRatesDataContext(context.Background(), p, DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = GetRatesDataContext(ctx, p, DataKindLatest)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("plain provider", func(t *testing.T) {
+		p := &plainProvider{}
+
+		data, err := GetRatesDataContext(context.Background(), p, DataKindTimeSeries)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("time-series"), data)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = GetRatesDataContext(ctx, p, DataKindTimeSeries)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, p.calls)
+	})
+}
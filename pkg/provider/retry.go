@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy defines how HTTPProvider retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It is doubled for every subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay limits delay between attempts, including delays requested by the Retry-After header.
+	// Zero value means no limit.
+	MaxDelay time.Duration
+
+	// Jitter is a fraction of the delay in the [0, 1] range which is randomly subtracted from it,
+	// so that multiple clients do not retry simultaneously.
+	Jitter float64
+
+	// Retryable reports whether the request should be retried given its response and error.
+	// resp is nil if no response was received; its body is already closed.
+	// DefaultRetryable is used if Retryable is nil.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns a sane retry policy: 4 attempts with exponential backoff starting at 1 second.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.5,
+		Retryable:   DefaultRetryable,
+	}
+}
+
+// DefaultRetryable reports whether a request should be retried.
+// Network errors, timeouts of a single attempt and 408, 429, 500, 502, 503 and 504 HTTP statuses are considered retryable.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrBodyTooLarge) {
+		return false
+	}
+
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return err != nil
+}
+
+// WithRetryPolicy sets policy used to retry failed requests.
+// Requests are not retried by default.
+func WithRetryPolicy(policy RetryPolicy) HTTPProviderOption {
+	return func(p *HTTPProvider) {
+		p.retryPolicy = policy
+	}
+}
+
+// retryable reports whether another attempt should be made after the given one.
+func (p RetryPolicy) retryable(attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Retryable == nil {
+		return DefaultRetryable(resp, err)
+	}
+	return p.Retryable(resp, err)
+}
+
+// delay returns time to wait before the retry following the given attempt.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if retryAfter, ok := parseRetryAfter(resp); ok {
+		return p.limit(retryAfter)
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	delay = p.limit(delay)
+
+	if p.Jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(delay))
+	}
+	return delay
+}
+
+// limit caps the given delay with MaxDelay.
+func (p RetryPolicy) limit(delay time.Duration) time.Duration {
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter parses Retry-After header of the response,
+// which contains either number of seconds to wait or an HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for the given duration or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/jieggii/ecbratex/tests"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDefaultRetryable(t *testing.T) {
+	someErr := errors.New("connection reset by peer")
+
+	type testCase struct {
+		resp      *http.Response
+		err       error
+		retryable bool
+	}
+	testCases := map[string]testCase{
+		"network error":         {nil, someErr, true},
+		"attempt timeout":       {nil, context.DeadlineExceeded, true},
+		"canceled":              {nil, context.Canceled, false},
+		"body too large":        {&http.Response{StatusCode: http.StatusOK}, ErrBodyTooLarge, false},
+		"body read error":       {&http.Response{StatusCode: http.StatusOK}, someErr, true},
+		"service unavailable":   {&http.Response{StatusCode: http.StatusServiceUnavailable}, someErr, true},
+		"internal server error": {&http.Response{StatusCode: http.StatusInternalServerError}, someErr, true},
+		"too many requests":     {&http.Response{StatusCode: http.StatusTooManyRequests}, someErr, true},
+		"not found":             {&http.Response{StatusCode: http.StatusNotFound}, someErr, false},
+		"forbidden":             {&http.Response{StatusCode: http.StatusForbidden}, someErr, false},
+		"success":               {&http.Response{StatusCode: http.StatusOK}, nil, false},
+	}
+
+	for name, c := range testCases {
+		assert.Equal(t, c.retryable, DefaultRetryable(c.resp, c.err), name)
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	t.Run("exponential backoff", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+		assert.Equal(t, time.Second, policy.delay(1, nil))
+		assert.Equal(t, 2*time.Second, policy.delay(2, nil))
+		assert.Equal(t, 4*time.Second, policy.delay(3, nil))
+		assert.Equal(t, 5*time.Second, policy.delay(4, nil))
+		assert.Equal(t, 5*time.Second, policy.delay(100, nil))
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}
+
+		for range 100 {
+			delay := policy.delay(1, nil)
+			assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+			assert.LessOrEqual(t, delay, time.Second)
+		}
+	})
+
+	t.Run("Retry-After in seconds", func(t *testing.T) {
+		var (
+			policy = RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+			resp   = &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+		)
+		assert.Equal(t, 7*time.Second, policy.delay(1, resp))
+	})
+
+	t.Run("Retry-After exceeding MaxDelay", func(t *testing.T) {
+		var (
+			policy = RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+			resp   = &http.Response{Header: http.Header{"Retry-After": {"3600"}}}
+		)
+		assert.Equal(t, time.Minute, policy.delay(1, resp))
+	})
+
+	t.Run("Retry-After as HTTP date", func(t *testing.T) {
+		var (
+			policy = RetryPolicy{BaseDelay: time.Second}
+			date   = time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+			resp   = &http.Response{Header: http.Header{"Retry-After": {date}}}
+		)
+		delay := policy.delay(1, resp)
+		assert.Greater(t, delay, 59*time.Minute)
+		assert.LessOrEqual(t, delay, time.Hour)
+	})
+
+	t.Run("invalid Retry-After", func(t *testing.T) {
+		var (
+			policy = RetryPolicy{BaseDelay: time.Second}
+			resp   = &http.Response{Header: http.Header{"Retry-After": {"soon"}}}
+		)
+		assert.Equal(t, time.Second, policy.delay(1, resp))
+	})
+}
+
+func TestHTTPProvider_Retry(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(2, http.StatusServiceUnavailable, nil)
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(policy),
+		)
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, data)
+		}
+		assert.Equal(t, 3, server.Requests())
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(5, http.StatusBadGateway, nil)
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(policy),
+		)
+		data, err := provider.GetRatesData(DataKindLatest)
+		if assert.Error(t, err) {
+			assert.Empty(t, data)
+		}
+		assert.Equal(t, 3, server.Requests())
+	})
+
+	t.Run("does not retry non-retryable status", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(1, http.StatusNotFound, nil)
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(policy),
+		)
+		_, err := provider.GetRatesData(DataKindLatest)
+		assert.Error(t, err)
+		assert.Equal(t, 1, server.Requests())
+	})
+
+	t.Run("does not retry without policy", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(1, http.StatusServiceUnavailable, nil)
+
+		provider := NewHTTPProvider(server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days)
+		_, err := provider.GetRatesData(DataKindLatest)
+		assert.Error(t, err)
+		assert.Equal(t, 1, server.Requests())
+	})
+
+	t.Run("custom retryable", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(1, http.StatusNotFound, nil)
+
+		customPolicy := policy
+		customPolicy.Retryable = func(resp *http.Response, err error) bool {
+			return resp != nil && resp.StatusCode == http.StatusNotFound
+		}
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(customPolicy),
+		)
+		_, err := provider.GetRatesData(DataKindLatest)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, server.Requests())
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		)
+
+		start := time.Now()
+		_, err := provider.GetRatesData(DataKindLatest)
+		if assert.NoError(t, err) {
+			assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		}
+	})
+
+	t.Run("context is done while waiting", func(t *testing.T) {
+		server := tests.NewTestHTTPServer(testDataPath, false)
+		defer server.Close()
+		server.FailNext(1, http.StatusServiceUnavailable, nil)
+
+		provider := NewHTTPProvider(
+			server.URLLatest, server.URLTimeSeries, server.URLTimeSeriesLast90Days,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour}),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		data, err := provider.GetRatesDataContext(ctx, DataKindLatest)
+		if assert.ErrorIs(t, err, context.DeadlineExceeded) {
+			assert.Empty(t, data)
+		}
+		assert.Equal(t, 1, server.Requests())
+	})
+}
@@ -0,0 +1,122 @@
+package provider
+
+import "time"
+
+// publicationHour is the hour of the day (in Central European Time) at which
+// the ECB publishes euro foreign exchange reference rates.
+const publicationHour = 16
+
+var (
+	cet  = time.FixedZone("CET", 1*60*60)
+	cest = time.FixedZone("CEST", 2*60*60)
+)
+
+// NextPublication returns the earliest expected time of publication of the reference rates strictly after t.
+// Reference rates are published around 16:00 CET on every TARGET business day.
+func NextPublication(t time.Time) time.Time {
+	day := civilDate(t)
+	for {
+		if IsTARGETBusinessDay(day) {
+			publication := publicationTime(day)
+			if publication.After(t) {
+				return publication
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// PreviousPublication returns the latest expected time of publication of the reference rates not after t.
+// Reference rates are published around 16:00 CET on every TARGET business day.
+func PreviousPublication(t time.Time) time.Time {
+	day := civilDate(t)
+	for {
+		if IsTARGETBusinessDay(day) {
+			publication := publicationTime(day)
+			if !publication.After(t) {
+				return publication
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// IsTARGETBusinessDay reports whether the given date (year, month and day are taken into account only)
+// is a TARGET business day, i.e. a day on which the ECB publishes reference rates.
+// TARGET is closed on weekends, New Year's Day, Good Friday, Easter Monday, 1 May, Christmas Day and 26 December.
+func IsTARGETBusinessDay(date time.Time) bool {
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	switch date.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+
+	switch {
+	case month == time.January && day == 1,
+		month == time.May && day == 1,
+		month == time.December && (day == 25 || day == 26):
+		return false
+	}
+
+	easter := easterSunday(year)
+	if date.Equal(easter.AddDate(0, 0, -2)) || date.Equal(easter.AddDate(0, 0, 1)) {
+		return false
+	}
+
+	return true
+}
+
+// civilDate returns midnight (UTC) of the date t falls on in Central European Time.
+func civilDate(t time.Time) time.Time {
+	year, month, day := t.In(centralEuropeanLocation(t)).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// publicationTime returns expected time of publication of the reference rates on the given date.
+func publicationTime(date time.Time) time.Time {
+	year, month, day := date.Date()
+	noon := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	return time.Date(year, month, day, publicationHour, 0, 0, 0, centralEuropeanLocation(noon))
+}
+
+// centralEuropeanLocation returns Central European Summer Time zone if daylight saving time is in effect
+// in the EU at the given instant (from 01:00 UTC of the last Sunday of March
+// till 01:00 UTC of the last Sunday of October) and Central European Time zone otherwise.
+// Rules are computed instead of loading "Europe/Berlin", so that no time zone database is required.
+func centralEuropeanLocation(t time.Time) *time.Location {
+	year := t.UTC().Year()
+	start := lastSunday(year, time.March).Add(time.Hour)
+	end := lastSunday(year, time.October).Add(time.Hour)
+	if !t.Before(start) && t.Before(end) {
+		return cest
+	}
+	return cet
+}
+
+// lastSunday returns midnight (UTC) of the last Sunday of the given month.
+func lastSunday(year int, month time.Month) time.Time {
+	date := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC) // last day of the month
+	return date.AddDate(0, 0, -int(date.Weekday()))
+}
+
+// easterSunday returns date of the Western Easter Sunday of the given year
+// computed using the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
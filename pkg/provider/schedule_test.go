@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestIsTARGETBusinessDay(t *testing.T) {
+	cases := map[time.Time]bool{
+		time.Date(2024, 2, 27, 0, 0, 0, 0, time.UTC):  true,  // Tuesday
+		time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC):   false, // Saturday
+		time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC):   false, // Sunday
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC):   false, // New Year's Day
+		time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC):  false, // Good Friday
+		time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC):   false, // Easter Monday
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC):   false, // Labour Day
+		time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC): true,  // Christmas Eve
+		time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC): false, // Christmas Day
+		time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC): false, // Boxing Day
+		time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC):  false, // Good Friday
+		time.Date(2025, 4, 22, 0, 0, 0, 0, time.UTC):  true,  // Tuesday after Easter Monday
+	}
+
+	for date, isBusinessDay := range cases {
+		assert.Equalf(t, isBusinessDay, IsTARGETBusinessDay(date), "date=%s", date.Format(time.DateOnly))
+	}
+}
+
+func TestEasterSunday(t *testing.T) {
+	cases := map[int]time.Time{
+		1999: time.Date(1999, 4, 4, 0, 0, 0, 0, time.UTC),
+		2008: time.Date(2008, 3, 23, 0, 0, 0, 0, time.UTC),
+		2019: time.Date(2019, 4, 21, 0, 0, 0, 0, time.UTC),
+		2024: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		2038: time.Date(2038, 4, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	for year, easter := range cases {
+		assert.Equalf(t, easter, easterSunday(year), "year=%d", year)
+	}
+}
+
+func TestNextPublication(t *testing.T) {
+	cases := map[time.Time]time.Time{
+		// winter time, before publication:
+		time.Date(2024, 2, 27, 10, 0, 0, 0, time.UTC): time.Date(2024, 2, 27, 15, 0, 0, 0, time.UTC),
+		// winter time, exactly at publication:
+		time.Date(2024, 2, 27, 15, 0, 0, 0, time.UTC): time.Date(2024, 2, 28, 15, 0, 0, 0, time.UTC),
+		// Friday after publication:
+		time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC): time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC),
+		// Thursday before Easter after publication (the next day is Good Friday, the day after DST starts):
+		time.Date(2024, 3, 28, 18, 0, 0, 0, time.UTC): time.Date(2024, 4, 2, 14, 0, 0, 0, time.UTC),
+		// summer time, before publication:
+		time.Date(2024, 7, 10, 13, 59, 0, 0, time.UTC): time.Date(2024, 7, 10, 14, 0, 0, 0, time.UTC),
+		// Christmas Eve after publication:
+		time.Date(2024, 12, 24, 20, 0, 0, 0, time.UTC): time.Date(2024, 12, 27, 15, 0, 0, 0, time.UTC),
+		// New Year's Eve late evening in CET, which is already the next day:
+		time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC): time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC),
+	}
+
+	for now, publication := range cases {
+		assert.Truef(t, publication.Equal(NextPublication(now)), "now=%s, got %s", now, NextPublication(now))
+	}
+}
+
+func TestPreviousPublication(t *testing.T) {
+	cases := map[time.Time]time.Time{
+		time.Date(2024, 2, 27, 10, 0, 0, 0, time.UTC):  time.Date(2024, 2, 26, 15, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 27, 15, 0, 0, 0, time.UTC):  time.Date(2024, 2, 27, 15, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC):    time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC):   time.Date(2024, 3, 28, 15, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 10, 14, 30, 0, 0, time.UTC): time.Date(2024, 7, 10, 14, 0, 0, 0, time.UTC),
+	}
+
+	for now, publication := range cases {
+		assert.Truef(t, publication.Equal(PreviousPublication(now)), "now=%s, got %s", now, PreviousPublication(now))
+	}
+}
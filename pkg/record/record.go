@@ -3,6 +3,9 @@ package record
 import (
 	"errors"
 	"fmt"
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/rounding"
 	"math"
 )
 
@@ -10,8 +13,66 @@ var (
 	// ErrRateNotFound error indicates that rate of
 	// the given currency is not present in a particular record.
 	ErrRateNotFound = errors.New("exchange rate was not found")
+
+	// ErrZeroRate error indicates that rate of the currency to convert to is zero.
+	ErrZeroRate = errors.New("exchange rate is zero")
 )
 
+// ConvertOption configures conversion.
+type ConvertOption func(o *convertOptions)
+
+// convertOptions are options of conversion methods.
+type convertOptions struct {
+	rounding  rounding.Mode
+	exponents bool
+}
+
+// newConvertOptions creates convertOptions configured with the given options.
+func newConvertOptions(opts []ConvertOption) convertOptions {
+	options := convertOptions{rounding: rounding.HalfAwayFromZero}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithRounding sets rounding mode used to round conversion result.
+// rounding.HalfAwayFromZero is used by default.
+func WithRounding(mode rounding.Mode) ConvertOption {
+	return func(o *convertOptions) {
+		o.rounding = mode
+	}
+}
+
+// WithCurrencyExponents makes minor-unit conversions rescale amounts between
+// ISO 4217 minor-unit exponents of the currencies (see [currency.Exponent]),
+// so that, for example, 1000 JPY (exponent 0) is converted to EUR cents (exponent 2).
+// Without this option, both currencies are assumed to have the same exponent.
+// Conversion of a currency without known exponent fails with [currency.ErrUnknownCurrency].
+func WithCurrencyExponents() ConvertOption {
+	return func(o *convertOptions) {
+		o.exponents = true
+	}
+}
+
+// exponentShift returns the difference between minor-unit exponents of the given currencies
+// if rescaling is enabled and zero otherwise.
+func (o convertOptions) exponentShift(from string, to string) (int, error) {
+	if !o.exponents {
+		return 0, nil
+	}
+
+	fromExponent, err := currency.Exponent(from)
+	if err != nil {
+		return 0, fmt.Errorf("get %s exponent: %w", from, err)
+	}
+	toExponent, err := currency.Exponent(to)
+	if err != nil {
+		return 0, fmt.Errorf("get %s exponent: %w", to, err)
+	}
+	return toExponent - fromExponent, nil
+}
+
 // Record is a type which represents rates record.
 // Each string (key) has its rate (value).
 type Record map[string]float32
@@ -46,7 +107,9 @@ func (r Record) Convert(amount float32, from string, to string) (float32, error)
 }
 
 // ConvertMinors converts amount in minor units from one to another.
-func (r Record) ConvertMinors(amount int64, from string, to string) (int64, error) {
+// The result is rounded using the rounding mode set by WithRounding.
+// Amounts are rescaled between minor-unit exponents of the currencies if WithCurrencyExponents is used.
+func (r Record) ConvertMinors(amount int64, from string, to string, opts ...ConvertOption) (int64, error) {
 	fromRate, found := r.Rate(from)
 	if !found {
 		return 0, fmt.Errorf("get %s rate: %w", from, ErrRateNotFound)
@@ -57,6 +120,214 @@ func (r Record) ConvertMinors(amount int64, from string, to string) (int64, erro
 		return 0, fmt.Errorf("get %s rate: %w", from, ErrRateNotFound)
 	}
 
+	options := newConvertOptions(opts)
+	shift, err := options.exponentShift(from, to)
+	if err != nil {
+		return 0, err
+	}
+
 	result := float32(amount) * (fromRate / toRate)
-	return int64(math.Round(float64(result))), nil
+	return int64(options.rounding.Round(float64(result) * math.Pow10(shift))), nil
+}
+
+// RateDecimal returns rate of the given currency as [decimal.Decimal] and a boolean indicating
+// whether currency was found in the rates record.
+// The rate is recovered from the shortest decimal representation of the stored float32,
+// see [decimal.NewFromFloat32]. It is exactly the published rate unless the rate has more
+// significant digits than float32 keeps, which is reported by validate.XMLData.
+func (r Record) RateDecimal(currency string) (decimal.Decimal, bool) {
+	rate, found := r[currency]
+	if !found {
+		return decimal.Zero, false
+	}
+
+	rateDecimal, err := decimal.NewFromFloat32(rate)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return rateDecimal, true
+}
+
+// ConvertDecimal converts amount from one currency to another using decimal arithmetic.
+// The result is rounded to [decimal.DivisionScale] fractional digits using the rounding mode set by WithRounding.
+func (r Record) ConvertDecimal(amount decimal.Decimal, from string, to string, opts ...ConvertOption) (decimal.Decimal, error) {
+	fromRate, toRate, err := r.decimalRates(from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return amount.Mul(fromRate).QuoMode(toRate, decimal.DivisionScale, newConvertOptions(opts).rounding), nil
+}
+
+// ConvertMinorsDecimal converts amount in minor units from one to another using decimal arithmetic.
+// Unlike ConvertMinors, the result is exact: it is rounded only once using the rounding mode set by WithRounding.
+// Amounts are rescaled between minor-unit exponents of the currencies if WithCurrencyExponents is used.
+func (r Record) ConvertMinorsDecimal(amount int64, from string, to string, opts ...ConvertOption) (int64, error) {
+	fromRate, toRate, err := r.decimalRates(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	options := newConvertOptions(opts)
+	shift, err := options.exponentShift(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return decimal.New(amount, int32(shift)).Mul(fromRate).QuoMode(toRate, 0, options.rounding).Int64()
+}
+
+// decimalRates returns decimal rates of the given currencies.
+func (r Record) decimalRates(from string, to string) (decimal.Decimal, decimal.Decimal, error) {
+	fromRate, found := r.RateDecimal(from)
+	if !found {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("get %s rate: %w", from, ErrRateNotFound)
+	}
+
+	toRate, found := r.RateDecimal(to)
+	if !found {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("get %s rate: %w", to, ErrRateNotFound)
+	}
+	if toRate.IsZero() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("get %s rate: %w", to, ErrZeroRate)
+	}
+
+	return fromRate, toRate, nil
+}
+
+// RateCurrency is like Rate, but accepts [currency.Currency].
+// The rate is not found if the currency is not known, see [currency.Codes].
+func (r Record) RateCurrency(c currency.Currency) (float32, bool) {
+	codes, err := currency.Codes(c)
+	if err != nil {
+		return 0, false
+	}
+	return r.Rate(codes[0])
+}
+
+// RateDecimalCurrency is like RateDecimal, but accepts [currency.Currency].
+// The rate is not found if the currency is not known, see [currency.Codes].
+func (r Record) RateDecimalCurrency(c currency.Currency) (decimal.Decimal, bool) {
+	codes, err := currency.Codes(c)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return r.RateDecimal(codes[0])
+}
+
+// ConvertCurrency is like Convert, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if any of the currencies is not known, see [currency.Codes].
+func (r Record) ConvertCurrency(amount float32, from currency.Currency, to currency.Currency) (float32, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return r.Convert(amount, codes[0], codes[1])
+}
+
+// ConvertMinorsCurrency is like ConvertMinors, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if any of the currencies is not known, see [currency.Codes].
+func (r Record) ConvertMinorsCurrency(amount int64, from currency.Currency, to currency.Currency, opts ...ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return r.ConvertMinors(amount, codes[0], codes[1], opts...)
+}
+
+// ConvertDecimalCurrency is like ConvertDecimal, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if any of the currencies is not known, see [currency.Codes].
+func (r Record) ConvertDecimalCurrency(amount decimal.Decimal, from currency.Currency, to currency.Currency, opts ...ConvertOption) (decimal.Decimal, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return r.ConvertDecimal(amount, codes[0], codes[1], opts...)
+}
+
+// ConvertMinorsDecimalCurrency is like ConvertMinorsDecimal, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if any of the currencies is not known, see [currency.Codes].
+func (r Record) ConvertMinorsDecimalCurrency(amount int64, from currency.Currency, to currency.Currency, opts ...ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return r.ConvertMinorsDecimal(amount, codes[0], codes[1], opts...)
+}
+
+// CrossRates is a matrix of cross rates indexed by the currency to convert from and the currency to convert to.
+type CrossRates map[string]map[string]float32
+
+// Rate returns cross rate of the given currencies and a boolean indicating whether it was found.
+func (c CrossRates) Rate(from string, to string) (float32, bool) {
+	rate, found := c[from][to]
+	return rate, found
+}
+
+// CrossRates returns matrix of cross rates between all pairs of the given currencies,
+// so that CrossRates(...)[from][to] is equal to Convert(1, from, to).
+// If no currencies are given, all currencies of the record are used.
+func (r Record) CrossRates(currencies ...string) (CrossRates, error) {
+	if len(currencies) == 0 {
+		currencies = make([]string, 0, len(r))
+		for c := range r {
+			currencies = append(currencies, c)
+		}
+	}
+
+	for _, c := range currencies {
+		if _, found := r[c]; !found {
+			return nil, fmt.Errorf("get %s rate: %w", c, ErrRateNotFound)
+		}
+	}
+
+	crossRates := make(CrossRates, len(currencies))
+	for _, from := range currencies {
+		row := make(map[string]float32, len(currencies))
+		for _, to := range currencies {
+			row[to] = r[from] / r[to]
+		}
+		crossRates[from] = row
+	}
+	return crossRates, nil
+}
+
+// CrossRatesCurrency is like CrossRates, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if any of the currencies is not known, see [currency.Codes].
+func (r Record) CrossRatesCurrency(currencies ...currency.Currency) (CrossRates, error) {
+	codes, err := currency.Codes(currencies...)
+	if err != nil {
+		return nil, err
+	}
+	return r.CrossRates(codes...)
+}
+
+// Rebase returns a new record containing rates relative to the given base currency instead of EUR:
+// rate of the base currency is 1 and rate of EUR is amount of EUR per 1 unit of the base currency.
+// Conversions using the rebased record give the same results as using the original one.
+func (r Record) Rebase(base string) (Record, error) {
+	baseRate, found := r[base]
+	if !found {
+		return nil, fmt.Errorf("get %s rate: %w", base, ErrRateNotFound)
+	}
+	if baseRate == 0 {
+		return nil, fmt.Errorf("get %s rate: %w", base, ErrZeroRate)
+	}
+
+	rebased := make(Record, len(r))
+	for c, rate := range r {
+		rebased[c] = rate / baseRate
+	}
+	rebased[base] = 1 // avoid rounding errors
+	return rebased, nil
+}
+
+// RebaseCurrency is like Rebase, but accepts [currency.Currency].
+// Returns [currency.ErrUnknownCurrency] if the currency is not known, see [currency.Codes].
+func (r Record) RebaseCurrency(base currency.Currency) (Record, error) {
+	codes, err := currency.Codes(base)
+	if err != nil {
+		return nil, err
+	}
+	return r.Rebase(codes[0])
 }
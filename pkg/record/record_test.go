@@ -1,7 +1,16 @@
 package record
 
 import (
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/rounding"
 	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"sort"
+	"strconv"
 	"testing"
 )
 
@@ -149,3 +158,380 @@ func TestRecord_ConvertMinors(t *testing.T) {
 		}
 	})
 }
+
+// testRates are rates of the 2024-02-27 record as published by the ECB.
+var testRates = map[string]string{
+	"USD": "1.0856", "JPY": "163.04", "GBP": "0.85620", "HUF": "390.20", "IDR": "16976.12",
+	"KRW": "1445.31", "CHF": "0.9544", "ISK": "149.30", "TRY": "33.8132", "EUR": "1",
+}
+
+// newTestRecord creates a new record containing testRates.
+func newTestRecord() Record {
+	record := New()
+	for currency, rate := range testRates {
+		value, err := strconv.ParseFloat(rate, 32)
+		if err != nil {
+			panic(err)
+		}
+		record[currency] = float32(value)
+	}
+	return record
+}
+
+// ratRate returns rate of the given currency from testRates as big.Rat.
+func ratRate(currency string) *big.Rat {
+	rate, ok := new(big.Rat).SetString(testRates[currency])
+	if !ok {
+		panic("invalid test rate")
+	}
+	return rate
+}
+
+// roundRat rounds r half away from zero to an integer.
+func roundRat(r *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Lsh(new(big.Int).Abs(rem), 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	}
+	return quo
+}
+
+func TestRecord_RateDecimal(t *testing.T) {
+	record := newTestRecord()
+
+	for currency, rate := range testRates {
+		actual, found := record.RateDecimal(currency)
+		if assert.True(t, found) {
+			assert.True(t, decimal.MustParse(rate).Equal(actual), "%s rate %s", currency, actual)
+		}
+	}
+
+	_, found := record.RateDecimal("XXX")
+	assert.False(t, found)
+}
+
+func TestRecord_ConvertDecimal(t *testing.T) {
+	record := newTestRecord()
+
+	t.Run("convert existing to existing", func(t *testing.T) {
+		result, err := record.ConvertDecimal(decimal.MustParse("10000000.00"), "JPY", "USD")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1501842299.1893883566691231", result.String())
+		}
+	})
+
+	t.Run("non-existent currency", func(t *testing.T) {
+		_, err := record.ConvertDecimal(decimal.NewFromInt(1), "XXX", "USD")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+
+		_, err = record.ConvertDecimal(decimal.NewFromInt(1), "USD", "XXX")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+
+	t.Run("zero rate", func(t *testing.T) {
+		_, err := Record{"USD": 1, "XXX": 0}.ConvertDecimal(decimal.NewFromInt(1), "USD", "XXX")
+		assert.ErrorIs(t, err, ErrZeroRate)
+	})
+}
+
+func TestRecord_ConvertMinorsDecimal(t *testing.T) {
+	record := newTestRecord()
+
+	t.Run("large amount", func(t *testing.T) {
+		const amount = 1_000_000_000 // 10,000,000.00 JPY
+
+		expected := roundRat(new(big.Rat).Quo(
+			new(big.Rat).Mul(new(big.Rat).SetInt64(amount), ratRate("JPY")),
+			ratRate("USD"),
+		))
+
+		actual, err := record.ConvertMinorsDecimal(amount, "JPY", "USD")
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected.Int64(), actual)
+		}
+
+		// float32 arithmetic loses minor units on such amounts:
+		float, err := record.ConvertMinors(amount, "JPY", "USD")
+		if assert.NoError(t, err) {
+			assert.NotEqual(t, expected.Int64(), float)
+		}
+	})
+
+	t.Run("agrees with math/big", func(t *testing.T) {
+		random := rand.New(rand.NewPCG(1, 2))
+		currencies := make([]string, 0, len(testRates))
+		for currency := range testRates {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+
+		for i := 0; i < 10000; i++ {
+			var (
+				amount = random.Int64N(2_000_000_000_000) - 1_000_000_000_000
+				from   = currencies[random.IntN(len(currencies))]
+				to     = currencies[random.IntN(len(currencies))]
+			)
+
+			expected := roundRat(new(big.Rat).Quo(
+				new(big.Rat).Mul(new(big.Rat).SetInt64(amount), ratRate(from)),
+				ratRate(to),
+			))
+
+			actual, err := record.ConvertMinorsDecimal(amount, from, to)
+			if !assert.NoError(t, err) || !assert.Equal(t, expected.Int64(), actual, "%d %s to %s", amount, from, to) {
+				return
+			}
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		_, err := record.ConvertMinorsDecimal(math.MaxInt64, "IDR", "GBP")
+		assert.ErrorIs(t, err, decimal.ErrOverflow)
+	})
+}
+
+func TestRecord_ConvertMinors_Rounding(t *testing.T) {
+	// converting from USD to XXX halves the amount, so odd amounts produce exact halves:
+	var record = Record{"USD": 1, "XXX": 2}
+
+	tests := []struct {
+		amount   int64
+		mode     rounding.Mode
+		expected int64
+	}{
+		{5, rounding.HalfAwayFromZero, 3},
+		{-5, rounding.HalfAwayFromZero, -3},
+		{5, rounding.HalfEven, 2},
+		{7, rounding.HalfEven, 4},
+		{-5, rounding.HalfEven, -2},
+		{-7, rounding.HalfEven, -4},
+		{5, rounding.Floor, 2},
+		{-5, rounding.Floor, -3},
+		{5, rounding.Ceil, 3},
+		{-5, rounding.Ceil, -2},
+		{5, rounding.Trunc, 2},
+		{-5, rounding.Trunc, -2},
+		{3, rounding.Floor, 1},
+		{-3, rounding.Ceil, -1},
+		{4, rounding.Floor, 2},
+		{-4, rounding.Ceil, -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", tt.amount, tt.mode), func(t *testing.T) {
+			result, err := record.ConvertMinors(tt.amount, "USD", "XXX", WithRounding(tt.mode))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, result)
+			}
+
+			result, err = record.ConvertMinorsDecimal(tt.amount, "USD", "XXX", WithRounding(tt.mode))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		result, err := record.ConvertMinors(-5, "USD", "XXX")
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(-3), result)
+		}
+	})
+
+	t.Run("decimal", func(t *testing.T) {
+		result, err := Record{"USD": 1, "XXX": 3}.ConvertDecimal(decimal.NewFromInt(2), "USD", "XXX", WithRounding(rounding.Floor))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "0.6666666666666666", result.String())
+		}
+	})
+}
+
+func TestRecord_ConvertMinors_CurrencyExponents(t *testing.T) {
+	var record = Record{"EUR": 1, "JPY": 2, "USD": 1}
+
+	tests := []struct {
+		amount   int64
+		from     string
+		to       string
+		expected int64
+	}{
+		{1000, "JPY", "EUR", 200000}, // 1000 JPY = 2000.00 EUR
+		{1000, "EUR", "JPY", 5},      // 10.00 EUR = 5 JPY
+		{1001, "EUR", "JPY", 5},      // 10.01 EUR = 5.005 JPY
+		{-1050, "EUR", "JPY", -5},    // -10.50 EUR = -5.25 JPY
+		{1234, "EUR", "USD", 1234},   // same exponents
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s to %s", tt.amount, tt.from, tt.to), func(t *testing.T) {
+			result, err := record.ConvertMinors(tt.amount, tt.from, tt.to, WithCurrencyExponents())
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, result)
+			}
+
+			result, err = record.ConvertMinorsDecimal(tt.amount, tt.from, tt.to, WithCurrencyExponents())
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("with rounding", func(t *testing.T) {
+		result, err := record.ConvertMinorsDecimal(1001, "EUR", "JPY", WithCurrencyExponents(), WithRounding(rounding.Ceil))
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(6), result)
+		}
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		var record = Record{"EUR": 1, "XXX": 2}
+
+		_, err := record.ConvertMinors(1000, "XXX", "EUR", WithCurrencyExponents())
+		assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+		_, err = record.ConvertMinorsDecimal(1000, "EUR", "XXX", WithCurrencyExponents())
+		assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+		// without rescaling exponents are not needed:
+		_, err = record.ConvertMinorsDecimal(1000, "EUR", "XXX")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRecord_Currency(t *testing.T) {
+	var record = Record{"EUR": 1, "USD": 2}
+
+	rate, found := record.RateCurrency(currency.USD)
+	if assert.True(t, found) {
+		assert.Equal(t, float32(2), rate)
+	}
+
+	rateDecimal, found := record.RateDecimalCurrency(currency.USD)
+	if assert.True(t, found) {
+		assert.Equal(t, "2", rateDecimal.String())
+	}
+
+	result, err := record.ConvertCurrency(10, currency.USD, currency.EUR)
+	if assert.NoError(t, err) {
+		assert.Equal(t, float32(20), result)
+	}
+
+	minors, err := record.ConvertMinorsCurrency(1000, currency.USD, currency.EUR)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(2000), minors)
+	}
+
+	decimalResult, err := record.ConvertDecimalCurrency(decimal.NewFromInt(10), currency.EUR, currency.USD)
+	if assert.NoError(t, err) {
+		assert.True(t, decimal.MustParse("5").Equal(decimalResult))
+	}
+
+	minors, err = record.ConvertMinorsDecimalCurrency(1000, currency.EUR, currency.USD)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(500), minors)
+	}
+
+	_, err = record.ConvertCurrency(10, currency.USD, currency.JPY)
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	t.Run("currencies are parsed", func(t *testing.T) {
+		rate, found := record.RateCurrency("usd")
+		if assert.True(t, found) {
+			assert.Equal(t, float32(2), rate)
+		}
+
+		_, found = record.RateDecimalCurrency("EURO")
+		assert.False(t, found)
+
+		_, err := record.ConvertCurrency(10, "EURO", currency.USD)
+		assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+		_, err = record.ConvertMinorsDecimalCurrency(1000, currency.EUR, "usd ")
+		assert.NoError(t, err)
+
+		_, err = record.CrossRatesCurrency(currency.USD, "US")
+		assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+		_, err = record.RebaseCurrency("")
+		assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+	})
+}
+
+func TestRecord_CrossRates(t *testing.T) {
+	var record = Record{"EUR": 1, "USD": 2, "JPY": 8}
+
+	t.Run("all currencies", func(t *testing.T) {
+		crossRates, err := record.CrossRates()
+		if assert.NoError(t, err) {
+			assert.Len(t, crossRates, 3)
+			for from := range record {
+				for to := range record {
+					expected, err := record.Convert(1, from, to)
+					if err != nil {
+						panic(err)
+					}
+
+					rate, found := crossRates.Rate(from, to)
+					if assert.True(t, found) {
+						assert.Equalf(t, expected, rate, "%s to %s", from, to)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("selected currencies", func(t *testing.T) {
+		crossRates, err := record.CrossRatesCurrency(currency.USD, currency.JPY)
+		if assert.NoError(t, err) {
+			assert.Equal(t, CrossRates{
+				"USD": {"USD": 1, "JPY": 0.25},
+				"JPY": {"USD": 4, "JPY": 1},
+			}, crossRates)
+		}
+
+		_, found := crossRates.Rate("EUR", "USD")
+		assert.False(t, found)
+	})
+
+	t.Run("non-existent currency", func(t *testing.T) {
+		crossRates, err := record.CrossRates("USD", "XXX")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+		assert.Nil(t, crossRates)
+	})
+}
+
+func TestRecord_Rebase(t *testing.T) {
+	var record = Record{"EUR": 1, "USD": 2, "JPY": 8}
+
+	t.Run("existing base", func(t *testing.T) {
+		rebased, err := record.RebaseCurrency(currency.USD)
+		if assert.NoError(t, err) {
+			assert.Equal(t, Record{"EUR": 0.5, "USD": 1, "JPY": 4}, rebased)
+
+			// conversions are not affected:
+			for from := range record {
+				for to := range record {
+					expected, _ := record.Convert(100, from, to)
+					actual, err := rebased.Convert(100, from, to)
+					if assert.NoError(t, err) {
+						assert.InDeltaf(t, expected, actual, 1e-3, "%s to %s", from, to)
+					}
+				}
+			}
+		}
+
+		// the original record is not modified:
+		assert.Equal(t, Record{"EUR": 1, "USD": 2, "JPY": 8}, record)
+	})
+
+	t.Run("non-existent base", func(t *testing.T) {
+		rebased, err := record.Rebase("XXX")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+		assert.Nil(t, rebased)
+	})
+
+	t.Run("zero base rate", func(t *testing.T) {
+		_, err := Record{"EUR": 1, "XXX": 0}.Rebase("XXX")
+		assert.ErrorIs(t, err, ErrZeroRate)
+	})
+}
@@ -0,0 +1,113 @@
+// Package rounding provides rounding modes used to round conversion results.
+package rounding
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Mode is a rounding mode.
+// The zero value is HalfAwayFromZero.
+type Mode uint8
+
+const (
+	// HalfAwayFromZero rounds to the nearest integer, rounding halves away from zero (commercial rounding).
+	// For example, 2.5 is rounded to 3 and -2.5 is rounded to -3.
+	HalfAwayFromZero Mode = iota
+
+	// HalfEven rounds to the nearest integer, rounding halves to the nearest even integer (banker's rounding).
+	// For example, 2.5 is rounded to 2, 3.5 is rounded to 4 and -2.5 is rounded to -2.
+	HalfEven
+
+	// Floor rounds toward negative infinity.
+	// For example, 2.7 is rounded to 2 and -2.1 is rounded to -3.
+	Floor
+
+	// Ceil rounds toward positive infinity.
+	// For example, 2.1 is rounded to 3 and -2.7 is rounded to -2.
+	Ceil
+
+	// Trunc rounds toward zero.
+	// For example, 2.7 is rounded to 2 and -2.7 is rounded to -2.
+	Trunc
+)
+
+// String returns name of the rounding mode.
+func (m Mode) String() string {
+	switch m {
+	case HalfAwayFromZero:
+		return "half-away-from-zero"
+	case HalfEven:
+		return "half-even"
+	case Floor:
+		return "floor"
+	case Ceil:
+		return "ceil"
+	case Trunc:
+		return "trunc"
+	default:
+		return fmt.Sprintf("Mode(%d)", m)
+	}
+}
+
+// Round rounds x to an integer.
+// Unknown modes are treated as HalfAwayFromZero.
+func (m Mode) Round(x float64) float64 {
+	switch m {
+	case HalfEven:
+		return math.RoundToEven(x)
+	case Floor:
+		return math.Floor(x)
+	case Ceil:
+		return math.Ceil(x)
+	case Trunc:
+		return math.Trunc(x)
+	default:
+		return math.Round(x)
+	}
+}
+
+// RoundQuo returns num / den rounded to an integer.
+// The result is exact. Unknown modes are treated as HalfAwayFromZero.
+func (m Mode) RoundQuo(num *big.Int, den *big.Int) *big.Int {
+	// quo is truncated toward zero:
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	negative := (num.Sign() < 0) != (den.Sign() < 0)
+	awayFromZero := false
+
+	switch m {
+	case Floor:
+		awayFromZero = negative
+	case Ceil:
+		awayFromZero = !negative
+	case Trunc:
+		awayFromZero = false
+	default:
+		// compare doubled remainder with denominator to find out whether the remainder is a half or more:
+		doubled := new(big.Int).Abs(rem)
+		doubled.Lsh(doubled, 1)
+
+		switch cmp := doubled.CmpAbs(den); {
+		case cmp > 0:
+			awayFromZero = true
+		case cmp == 0 && m == HalfEven:
+			awayFromZero = quo.Bit(0) == 1
+		case cmp == 0:
+			awayFromZero = true
+		}
+	}
+
+	if awayFromZero {
+		if negative {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
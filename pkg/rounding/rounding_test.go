@@ -0,0 +1,55 @@
+package rounding
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// roundingTests are values multiplied by 10 and their expected rounded values for each mode.
+var roundingTests = []struct {
+	tenths   int64
+	expected map[Mode]int64
+}{
+	{25, map[Mode]int64{HalfAwayFromZero: 3, HalfEven: 2, Floor: 2, Ceil: 3, Trunc: 2}},
+	{35, map[Mode]int64{HalfAwayFromZero: 4, HalfEven: 4, Floor: 3, Ceil: 4, Trunc: 3}},
+	{-25, map[Mode]int64{HalfAwayFromZero: -3, HalfEven: -2, Floor: -3, Ceil: -2, Trunc: -2}},
+	{-35, map[Mode]int64{HalfAwayFromZero: -4, HalfEven: -4, Floor: -4, Ceil: -3, Trunc: -3}},
+	{5, map[Mode]int64{HalfAwayFromZero: 1, HalfEven: 0, Floor: 0, Ceil: 1, Trunc: 0}},
+	{-5, map[Mode]int64{HalfAwayFromZero: -1, HalfEven: 0, Floor: -1, Ceil: 0, Trunc: 0}},
+	{24, map[Mode]int64{HalfAwayFromZero: 2, HalfEven: 2, Floor: 2, Ceil: 3, Trunc: 2}},
+	{26, map[Mode]int64{HalfAwayFromZero: 3, HalfEven: 3, Floor: 2, Ceil: 3, Trunc: 2}},
+	{-24, map[Mode]int64{HalfAwayFromZero: -2, HalfEven: -2, Floor: -3, Ceil: -2, Trunc: -2}},
+	{-26, map[Mode]int64{HalfAwayFromZero: -3, HalfEven: -3, Floor: -3, Ceil: -2, Trunc: -2}},
+	{20, map[Mode]int64{HalfAwayFromZero: 2, HalfEven: 2, Floor: 2, Ceil: 2, Trunc: 2}},
+	{-20, map[Mode]int64{HalfAwayFromZero: -2, HalfEven: -2, Floor: -2, Ceil: -2, Trunc: -2}},
+	{0, map[Mode]int64{HalfAwayFromZero: 0, HalfEven: 0, Floor: 0, Ceil: 0, Trunc: 0}},
+}
+
+func TestMode_Round(t *testing.T) {
+	for _, tt := range roundingTests {
+		for mode, expected := range tt.expected {
+			t.Run(mode.String(), func(t *testing.T) {
+				assert.Equalf(t, float64(expected), mode.Round(float64(tt.tenths)/10), "%d / 10", tt.tenths)
+			})
+		}
+	}
+}
+
+func TestMode_RoundQuo(t *testing.T) {
+	for _, tt := range roundingTests {
+		for mode, expected := range tt.expected {
+			t.Run(mode.String(), func(t *testing.T) {
+				assert.Equalf(t, expected, mode.RoundQuo(big.NewInt(tt.tenths), big.NewInt(10)).Int64(), "%d / 10", tt.tenths)
+
+				// negative denominator:
+				assert.Equalf(t, expected, mode.RoundQuo(big.NewInt(-tt.tenths), big.NewInt(-10)).Int64(), "%d / -10", -tt.tenths)
+			})
+		}
+	}
+}
+
+func TestMode_String(t *testing.T) {
+	assert.Equal(t, "half-even", HalfEven.String())
+	assert.Equal(t, "Mode(42)", Mode(42).String())
+}
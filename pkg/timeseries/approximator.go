@@ -0,0 +1,238 @@
+package timeseries
+
+import (
+	"github.com/jieggii/ecbratex/pkg/record"
+	"math"
+	"sort"
+)
+
+// Observation is a rate of a currency published in the record of the given date.
+type Observation struct {
+	// Date is date of the record the rate was taken from.
+	Date record.Date
+
+	// Rate is the published rate.
+	Rate float32
+}
+
+// Approximation is an approximated rate of a currency.
+type Approximation struct {
+	// Rate is the approximated rate.
+	Rate float32
+
+	// Sources are dates of records the rate was derived from in chronological order.
+	Sources []record.Date
+
+	// Provenance is filled by Records methods, approximators do not need to set it.
+	Provenance
+}
+
+// RatesApproximation is an approximated rates record.
+type RatesApproximation struct {
+	// Rates are the approximated rates.
+	Rates record.Record
+
+	// Sources are dates of records the rates were derived from in chronological order.
+	Sources []record.Date
+
+	Provenance
+}
+
+// Approximator is a strategy of approximating rate of a currency on a date there is no record for
+// using rates of the nearest earlier and later records.
+type Approximator struct {
+	// Name is name of the strategy, for example "linear".
+	Name string
+
+	// Approximate approximates rate on the given date using the nearest earlier and later observations.
+	// Either earlier or later is nil if there is no such observation within the range limit, but never both.
+	// It returns the approximation and a boolean indicating whether the rate could be approximated.
+	Approximate func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool)
+}
+
+var (
+	// Midpoint takes the average of the earlier and later rates regardless of their distance to the date,
+	// or the only available rate. It is used by ApproximateRates and ApproximateRate.
+	Midpoint = Approximator{
+		Name: "midpoint",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			if earlier == nil || later == nil {
+				return approximateFromAvailable(earlier, later)
+			}
+			return Approximation{
+				Rate:    (earlier.Rate + later.Rate) / 2,
+				Sources: []record.Date{earlier.Date, later.Date},
+			}, true
+		},
+	}
+
+	// PreviousAvailable takes the earlier rate (last observation carried forward), as accounting rules usually require.
+	// It fails if there is no earlier rate.
+	PreviousAvailable = Approximator{
+		Name: "previous-available",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			return approximateFromAvailable(earlier, nil)
+		},
+	}
+
+	// NextAvailable takes the later rate.
+	// It fails if there is no later rate.
+	NextAvailable = Approximator{
+		Name: "next-available",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			return approximateFromAvailable(nil, later)
+		},
+	}
+
+	// Nearest takes the rate which is the nearest to the date or the earlier one if both are equally distant.
+	Nearest = Approximator{
+		Name: "nearest",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			if earlier == nil || later == nil {
+				return approximateFromAvailable(earlier, later)
+			}
+			if later.Date.SubDays(date) < date.SubDays(earlier.Date) {
+				return approximateFromAvailable(nil, later)
+			}
+			return approximateFromAvailable(earlier, nil)
+		},
+	}
+
+	// Linear interpolates between the earlier and later rates weighting them by their distance to the date,
+	// or takes the only available rate.
+	Linear = Approximator{
+		Name: "linear",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			if earlier == nil || later == nil {
+				return approximateFromAvailable(earlier, later)
+			}
+			e, l := float64(earlier.Rate), float64(later.Rate)
+			return Approximation{
+				Rate:    float32(e + (l-e)*interpolationWeight(date, earlier, later)),
+				Sources: []record.Date{earlier.Date, later.Date},
+			}, true
+		},
+	}
+
+	// Geometric interpolates between logarithms of the earlier and later rates weighting them by their distance
+	// to the date, or takes the only available rate. Unlike Linear, it treats rates of both directions symmetrically:
+	// approximated rate of A per B is exactly inverse of approximated rate of B per A.
+	// It fails if any of the rates is not positive.
+	Geometric = Approximator{
+		Name: "geometric",
+		Approximate: func(date record.Date, earlier *Observation, later *Observation) (Approximation, bool) {
+			if earlier == nil || later == nil {
+				return approximateFromAvailable(earlier, later)
+			}
+			if !(earlier.Rate > 0) || !(later.Rate > 0) {
+				return Approximation{}, false
+			}
+			e, l := float64(earlier.Rate), float64(later.Rate)
+			return Approximation{
+				Rate:    float32(e * math.Pow(l/e, interpolationWeight(date, earlier, later))),
+				Sources: []record.Date{earlier.Date, later.Date},
+			}, true
+		},
+	}
+)
+
+// approximateFromAvailable returns the earlier observation or the later one if there is no earlier observation.
+func approximateFromAvailable(earlier *Observation, later *Observation) (Approximation, bool) {
+	switch {
+	case earlier != nil:
+		return Approximation{Rate: earlier.Rate, Sources: []record.Date{earlier.Date}}, true
+	case later != nil:
+		return Approximation{Rate: later.Rate, Sources: []record.Date{later.Date}}, true
+	default:
+		return Approximation{}, false
+	}
+}
+
+// interpolationWeight returns weight of the later observation: 0 on the earlier date and 1 on the later date.
+func interpolationWeight(date record.Date, earlier *Observation, later *Observation) float64 {
+	return float64(date.SubDays(earlier.Date)) / float64(later.Date.SubDays(earlier.Date))
+}
+
+// approximateRate approximates rate of the currency on the given date using its nearest earlier and later
+// observations found in the given window of the records (the nearest records first, see window methods).
+// Rate of a currency which is missing from all records on one side of the window is not approximated,
+// because the currency is not published on the given date, see missingRateError.
+func approximateRate(recDate record.Date, currency string, earlier []record.WithDate, later []record.WithDate, approximator Approximator) (Approximation, bool) {
+	earlierObservation, laterObservation, ok := nearestObservations(earlier, later, currency)
+	if !ok {
+		return Approximation{}, false
+	}
+
+	approximation, ok := approximator.Approximate(recDate, earlierObservation, laterObservation)
+	if !ok {
+		return Approximation{}, false
+	}
+	approximation.Provenance = newProvenance(recDate, approximator.Name, approximation.Sources)
+	return approximation, true
+}
+
+// approximateRates approximates rates of all currencies found in the given window of the records on the given date,
+// see approximateRate.
+func approximateRates(recDate record.Date, earlier []record.WithDate, later []record.WithDate, approximator Approximator) (RatesApproximation, bool) {
+	currencies := make(map[string]struct{})
+	for _, records := range [][]record.WithDate{earlier, later} {
+		for _, rec := range records {
+			for currency := range rec.Record {
+				currencies[currency] = struct{}{}
+			}
+		}
+	}
+
+	rates := record.New()
+	sources := make(map[record.Date]struct{}, 2)
+	for currency := range currencies {
+		approximation, ok := approximateRate(recDate, currency, earlier, later, approximator)
+		if !ok {
+			continue
+		}
+		rates[currency] = approximation.Rate
+		for _, source := range approximation.Sources {
+			sources[source] = struct{}{}
+		}
+	}
+	if len(rates) == 0 {
+		return RatesApproximation{}, false
+	}
+
+	result := RatesApproximation{Rates: rates, Sources: make([]record.Date, 0, len(sources))}
+	for source := range sources {
+		result.Sources = append(result.Sources, source)
+	}
+	sort.Slice(result.Sources, func(i, j int) bool {
+		return result.Sources[i].Before(result.Sources[j])
+	})
+	result.Provenance = newProvenance(recDate, approximator.Name, result.Sources)
+	return result, true
+}
+
+// nearestObservations returns the nearest earlier and later observations of the currency in the given window
+// and a boolean indicating whether they can be used for approximation: the currency has to be observed
+// on each side of the window having records. Otherwise, if records on one side of the window do not contain
+// the currency, it is not carried over them from the other side.
+func nearestObservations(earlier []record.WithDate, later []record.WithDate, currency string) (*Observation, *Observation, bool) {
+	earlierObservation := nearestObservation(earlier, currency)
+	laterObservation := nearestObservation(later, currency)
+	if earlierObservation == nil && laterObservation == nil {
+		return nil, nil, false
+	}
+	if (earlierObservation == nil && len(earlier) != 0) || (laterObservation == nil && len(later) != 0) {
+		return nil, nil, false
+	}
+	return earlierObservation, laterObservation, true
+}
+
+// nearestObservation returns rate of the currency in the first of the given records containing it
+// or nil if there is no such record.
+func nearestObservation(records []record.WithDate, currency string) *Observation {
+	for _, rec := range records {
+		if rate, found := rec.Record[currency]; found {
+			return &Observation{Date: rec.Date, Rate: rate}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package timeseries
+
+import (
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApproximators(t *testing.T) {
+	var (
+		earlier = &Observation{Date: record.NewDate(2024, 2, 23), Rate: 1} // Friday
+		later   = &Observation{Date: record.NewDate(2024, 2, 27), Rate: 4} // Tuesday
+		date    = record.NewDate(2024, 2, 24)                              // Saturday, 1 day after earlier and 3 days before later
+		both    = []record.Date{earlier.Date, later.Date}
+	)
+
+	tests := []struct {
+		approximator Approximator
+		expected     Approximation
+	}{
+		{approximator: Midpoint, expected: Approximation{Rate: 2.5, Sources: both}},
+		{approximator: PreviousAvailable, expected: Approximation{Rate: 1, Sources: []record.Date{earlier.Date}}},
+		{approximator: NextAvailable, expected: Approximation{Rate: 4, Sources: []record.Date{later.Date}}},
+		{approximator: Nearest, expected: Approximation{Rate: 1, Sources: []record.Date{earlier.Date}}},
+		{approximator: Linear, expected: Approximation{Rate: 1.75, Sources: both}},
+		{approximator: Geometric, expected: Approximation{Rate: 1.4142135, Sources: both}}, // 1 * 4^(1/4)
+	}
+
+	for _, test := range tests {
+		t.Run(test.approximator.Name, func(t *testing.T) {
+			approximation, ok := test.approximator.Approximate(date, earlier, later)
+			if assert.True(t, ok) {
+				assert.Equal(t, test.expected, approximation)
+			}
+
+			t.Run("only earlier observation", func(t *testing.T) {
+				approximation, ok := test.approximator.Approximate(date, earlier, nil)
+				if test.approximator.Name == NextAvailable.Name {
+					assert.False(t, ok)
+					return
+				}
+				if assert.True(t, ok) {
+					assert.Equal(t, Approximation{Rate: 1, Sources: []record.Date{earlier.Date}}, approximation)
+				}
+			})
+
+			t.Run("only later observation", func(t *testing.T) {
+				approximation, ok := test.approximator.Approximate(date, nil, later)
+				if test.approximator.Name == PreviousAvailable.Name {
+					assert.False(t, ok)
+					return
+				}
+				if assert.True(t, ok) {
+					assert.Equal(t, Approximation{Rate: 4, Sources: []record.Date{later.Date}}, approximation)
+				}
+			})
+		})
+	}
+
+	t.Run("nearest later observation", func(t *testing.T) {
+		approximation, ok := Nearest.Approximate(record.NewDate(2024, 2, 26), earlier, later)
+		if assert.True(t, ok) {
+			assert.Equal(t, Approximation{Rate: 4, Sources: []record.Date{later.Date}}, approximation)
+		}
+	})
+
+	t.Run("equally distant observations", func(t *testing.T) {
+		approximation, ok := Nearest.Approximate(record.NewDate(2024, 2, 25), earlier, later)
+		if assert.True(t, ok) {
+			assert.Equal(t, Approximation{Rate: 1, Sources: []record.Date{earlier.Date}}, approximation)
+		}
+	})
+
+	t.Run("geometric interpolation is symmetric", func(t *testing.T) {
+		inverse, ok := Geometric.Approximate(
+			date,
+			&Observation{Date: earlier.Date, Rate: 1 / earlier.Rate},
+			&Observation{Date: later.Date, Rate: 1 / later.Rate},
+		)
+		direct, _ := Geometric.Approximate(date, earlier, later)
+		if assert.True(t, ok) {
+			assert.InDelta(t, 1, inverse.Rate*direct.Rate, 1e-6)
+		}
+	})
+
+	t.Run("geometric interpolation of non-positive rates", func(t *testing.T) {
+		_, ok := Geometric.Approximate(date, &Observation{Date: earlier.Date, Rate: 0}, later)
+		assert.False(t, ok)
+	})
+}
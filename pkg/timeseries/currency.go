@@ -0,0 +1,161 @@
+package timeseries
+
+import (
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/date"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/record"
+)
+
+// This file contains variants of the Records methods accepting [currency.Currency] instead of strings.
+// Currencies are parsed before use (see [currency.Codes]), so that unknown currencies
+// are reported as [currency.ErrUnknownCurrency] instead of [record.ErrRateNotFound].
+
+// Rate is like Records.Rate, but accepts [currency.Currency].
+// Returns [record.ErrRateNotFound] if there is no rate.
+func Rate(records Records, date date.Date, c currency.Currency) (float32, error) {
+	codes, err := currency.Codes(c)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, found := records.Rate(date, codes[0])
+	if !found {
+		return 0, fmt.Errorf("get %s rate: %w", codes[0], record.ErrRateNotFound)
+	}
+	return rate, nil
+}
+
+// ApproximateRate is like Records.ApproximateRate, but accepts [currency.Currency].
+// Returns error wrapping ErrRateApproximationFailed if the rate could not be approximated.
+func ApproximateRate(records Records, date date.Date, c currency.Currency, rangeLim int) (float32, error) {
+	approximation, err := ApproximateRateWith(records, date, c, rangeLim, Midpoint)
+	return approximation.Rate, err
+}
+
+// ApproximateRateWith is like Records.ApproximateRateWith, but accepts [currency.Currency].
+// Returns error wrapping ErrRateApproximationFailed if the rate could not be approximated.
+func ApproximateRateWith(records Records, date date.Date, c currency.Currency, rangeLim int, approximator Approximator) (Approximation, error) {
+	codes, err := currency.Codes(c)
+	if err != nil {
+		return Approximation{}, err
+	}
+
+	approximation, ok := records.ApproximateRateWith(date, codes[0], rangeLim, approximator)
+	if !ok {
+		if source, ok := records.(approximationSource); ok {
+			return Approximation{}, missingRateError(source, record.DateFromDate(date), codes[0], rangeLim)
+		}
+		return Approximation{}, fmt.Errorf("approximate %s rate: %w", codes[0], ErrRateApproximationFailed)
+	}
+	return approximation, nil
+}
+
+// Convert is like Records.Convert, but accepts [currency.Currency].
+func Convert(records Records, date date.Date, amount float32, from currency.Currency, to currency.Currency) (float32, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.Convert(date, amount, codes[0], codes[1])
+}
+
+// ConvertApproximate is like Records.ConvertApproximate, but accepts [currency.Currency].
+func ConvertApproximate(records Records, date date.Date, amount float32, from currency.Currency, to currency.Currency, rangeLim int) (float32, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.ConvertApproximate(date, amount, codes[0], codes[1], rangeLim)
+}
+
+// ConvertApproximateWith is like Records.ConvertApproximateWith, but accepts [currency.Currency].
+func ConvertApproximateWith(records Records, date date.Date, amount float32, from currency.Currency, to currency.Currency, rangeLim int, approximator Approximator) (ApproximatedConversion[float32], error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return ApproximatedConversion[float32]{}, err
+	}
+	return records.ConvertApproximateWith(date, amount, codes[0], codes[1], rangeLim, approximator)
+}
+
+// ConvertMinors is like Records.ConvertMinors, but accepts [currency.Currency].
+func ConvertMinors(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, opts ...record.ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.ConvertMinors(date, amount, codes[0], codes[1], opts...)
+}
+
+// ConvertMinorsApproximate is like Records.ConvertMinorsApproximate, but accepts [currency.Currency].
+func ConvertMinorsApproximate(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.ConvertMinorsApproximate(date, amount, codes[0], codes[1], rangeLim, opts...)
+}
+
+// ConvertMinorsApproximateWith is like Records.ConvertMinorsApproximateWith, but accepts [currency.Currency].
+func ConvertMinorsApproximateWith(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+	return records.ConvertMinorsApproximateWith(date, amount, codes[0], codes[1], rangeLim, approximator, opts...)
+}
+
+// ConvertDecimal is like Records.ConvertDecimal, but accepts [currency.Currency].
+func ConvertDecimal(records Records, date date.Date, amount decimal.Decimal, from currency.Currency, to currency.Currency, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return records.ConvertDecimal(date, amount, codes[0], codes[1], opts...)
+}
+
+// ConvertDecimalApproximate is like Records.ConvertDecimalApproximate, but accepts [currency.Currency].
+func ConvertDecimalApproximate(records Records, date date.Date, amount decimal.Decimal, from currency.Currency, to currency.Currency, rangeLim int, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return records.ConvertDecimalApproximate(date, amount, codes[0], codes[1], rangeLim, opts...)
+}
+
+// ConvertDecimalApproximateWith is like Records.ConvertDecimalApproximateWith, but accepts [currency.Currency].
+func ConvertDecimalApproximateWith(records Records, date date.Date, amount decimal.Decimal, from currency.Currency, to currency.Currency, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[decimal.Decimal], error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return ApproximatedConversion[decimal.Decimal]{}, err
+	}
+	return records.ConvertDecimalApproximateWith(date, amount, codes[0], codes[1], rangeLim, approximator, opts...)
+}
+
+// ConvertMinorsDecimal is like Records.ConvertMinorsDecimal, but accepts [currency.Currency].
+func ConvertMinorsDecimal(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, opts ...record.ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.ConvertMinorsDecimal(date, amount, codes[0], codes[1], opts...)
+}
+
+// ConvertMinorsDecimalApproximate is like Records.ConvertMinorsDecimalApproximate, but accepts [currency.Currency].
+func ConvertMinorsDecimalApproximate(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return records.ConvertMinorsDecimalApproximate(date, amount, codes[0], codes[1], rangeLim, opts...)
+}
+
+// ConvertMinorsDecimalApproximateWith is like Records.ConvertMinorsDecimalApproximateWith, but accepts [currency.Currency].
+func ConvertMinorsDecimalApproximateWith(records Records, date date.Date, amount int64, from currency.Currency, to currency.Currency, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	codes, err := currency.Codes(from, to)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+	return records.ConvertMinorsDecimalApproximateWith(date, amount, codes[0], codes[1], rangeLim, approximator, opts...)
+}
@@ -2,12 +2,21 @@ package timeseries
 
 import (
 	"github.com/jieggii/ecbratex/pkg/date"
+	"github.com/jieggii/ecbratex/pkg/decimal"
 	"github.com/jieggii/ecbratex/pkg/record"
 	"github.com/jieggii/ecbratex/pkg/xml"
+	"io"
+	"slices"
+	"sort"
 )
 
 // OrderedRecords is an implementation of the Records interface.
 // It stores records in a sorted slice, so the order of records is easily accessible.
+// Records are expected to be in anti-chronological order, because lookups use binary search.
+// Unsorted records (for example, manually created in chronological order) are detected and looked up
+// in a slower way instead. Approximations only check order of records around the approximated date
+// and at the ends of the slice, so use Sort for manually created slices which may be shuffled.
+// Constructors of this package sort records if needed.
 type OrderedRecords []record.WithDate
 
 // NewOrderedRecords creates new empty OrderedRecords.
@@ -17,21 +26,34 @@ func NewOrderedRecords() OrderedRecords {
 
 // NewOrderedRecordsFromXML creates new OrderedRecords from [xml.Data].
 func NewOrderedRecordsFromXML(xmlData *xml.Data) (OrderedRecords, error) {
-	records := NewOrderedRecords()
-
+	records := make(OrderedRecords, 0, len(xmlData.Cubes))
 	for _, cube := range xmlData.Cubes {
-		rec := record.New()
-		for _, rate := range cube.Rates {
-			rec[rate.Currency] = rate.Rate
+		recDate, rec, err := newRecordFromCube(cube)
+		if err != nil {
+			return nil, err
 		}
-		rec["EUR"] = 1 // add EUR rate for convenience
+		records = append(records, record.NewWithDate(rec, recDate))
+	}
+	records.sortIfNeeded()
+	return records, nil
+}
 
-		recDate, err := record.DateFromString(cube.Date)
+// NewOrderedRecordsFromReader creates new OrderedRecords from XML document read from r.
+// Unlike NewOrderedRecordsFromXML, it does not keep the whole decoded document in memory, see [xml.DecodeCubes].
+func NewOrderedRecordsFromReader(r io.Reader) (OrderedRecords, error) {
+	records := NewOrderedRecords()
+	err := xml.DecodeCubes(r, func(cube xml.DataCube) error {
+		recDate, rec, err := newRecordFromCube(cube)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		records = append(records, record.NewWithDate(rec, recDate))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	records.sortIfNeeded()
 	return records, nil
 }
 
@@ -51,10 +73,61 @@ func (r OrderedRecords) Map() map[record.Date]record.Record {
 	return result
 }
 
+// Len returns number of records.
+// Operates on O(1) time complexity.
+func (r OrderedRecords) Len() int {
+	return len(r)
+}
+
+// First returns the earliest record and a boolean indicating whether there are any records.
+// Records must be sorted, see IsSorted.
+// Operates on O(1) time complexity.
+func (r OrderedRecords) First() (record.WithDate, bool) {
+	if len(r) == 0 {
+		return record.WithDate{}, false
+	}
+	return r[len(r)-1], true
+}
+
+// Last returns the latest record and a boolean indicating whether there are any records.
+// Records must be sorted, see IsSorted.
+// Operates on O(1) time complexity.
+func (r OrderedRecords) Last() (record.WithDate, bool) {
+	if len(r) == 0 {
+		return record.WithDate{}, false
+	}
+	return r[0], true
+}
+
+// Range returns OrderedRecords containing records dated within the [from, to] interval.
+// The returned records share the underlying array with r if r is sorted, otherwise they are copied and sorted.
+// Operates on O(n) time complexity to check that records are sorted, see IsSorted.
+func (r OrderedRecords) Range(from date.Date, to date.Date) Records {
+	if !r.IsSorted() {
+		return r.sortedCopy().Range(from, to)
+	}
+	fromDate, toDate := record.DateFromDate(from), record.DateFromDate(to)
+
+	start, end := r.searchNotAfter(toDate), r.searchBefore(fromDate)
+	if start >= end {
+		return NewOrderedRecords()
+	}
+	return r[start:end:end]
+}
+
 // Rates returns rates on the given date.
-// Operates on O(n) time complexity.
+// Operates on O(log n) time complexity if there is a record of the given date, otherwise on O(n),
+// because records which are not sorted (see IsSorted) are scanned linearly.
 func (r OrderedRecords) Rates(date date.Date) (record.Record, bool) {
 	recDate := record.DateFromDate(date)
+	i := r.searchNotAfter(recDate)
+	if i < len(r) && r[i].Date == recDate {
+		return r[i].Record, true
+	}
+	if r.IsSorted() {
+		return nil, false
+	}
+
 	for _, rec := range r {
 		if rec.Date == recDate {
 			return rec.Record, true
@@ -64,7 +137,7 @@ func (r OrderedRecords) Rates(date date.Date) (record.Record, bool) {
 }
 
 // Rate returns rate of the given currency on the given date.
-// Operates on O(n) time complexity.
+// Operates on O(log n) time complexity if there is a record of the given date, see Rates.
 func (r OrderedRecords) Rate(date date.Date, string string) (float32, bool) {
 	rec, found := r.Rates(date)
 	if !found {
@@ -79,94 +152,39 @@ func (r OrderedRecords) Rate(date date.Date, string string) (float32, bool) {
 	return rate, true
 }
 
-// ApproximateRates approximates and returns approximated rates on the given date.
-// Operates on O(n) time complexity.
+// ApproximateRates approximates and returns approximated rates on the given date using [Midpoint] approximator.
+// Operates on O(log n + rangeLim) time complexity.
 func (r OrderedRecords) ApproximateRates(date date.Date, rangeLim int) (record.Record, bool) {
-	recDate := record.DateFromDate(date)
-
-	// find the nearest earlier and later rate records:
-	earlierRec, earlierRecFound := r.nearestEarlierRecord(recDate, rangeLim)
-	laterRec, laterRecFound := r.nearestLaterRecord(recDate, rangeLim)
-
-	// if neither earlier nor later rate records were found:
-	if !earlierRecFound && !laterRecFound {
-		return nil, false
-	}
-
-	// return later record if earlier record was not found:
-	if !earlierRecFound {
-		return laterRec, true
-	}
-
-	// return earlier record if later record was not found:
-	if !laterRecFound {
-		return earlierRec, true
-	}
-
-	// approximate the closest earlier and later records if both were found
-	// (calculating average where possible or using later or earlier rates):
-	rec := record.New()
-	for currency, earlierRate := range earlierRec {
-		laterRate, found := laterRec.Rate(currency)
-		if !found {
-			rec[currency] = earlierRate
-			continue
-		}
-		rec[currency] = (earlierRate + laterRate) / 2
-	}
-
-	// fill ratesRecord with possible missing rates from later records:
-	for currency, laterRate := range laterRec {
-		_, found := rec[currency]
-		if !found {
-			rec[currency] = laterRate
-		}
-	}
+	approximation, ok := r.ApproximateRatesWith(date, rangeLim, Midpoint)
+	return approximation.Rates, ok
+}
 
-	return rec, true
+// ApproximateRate approximates and returns approximated rate of the given currency on the given date
+// using [Midpoint] approximator.
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ApproximateRate(date date.Date, currency string, rangeLim int) (float32, bool) {
+	approximation, ok := r.ApproximateRateWith(date, currency, rangeLim, Midpoint)
+	return approximation.Rate, ok
 }
 
-// ApproximateRate approximates and returns approximated rate of the given currency on the given date.
-// Operates on O(n) time complexity.
-func (r OrderedRecords) ApproximateRate(date date.Date, string string, rangeLim int) (float32, bool) {
+// ApproximateRatesWith approximates rates on the given date using the given approximator.
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ApproximateRatesWith(date date.Date, rangeLim int, approximator Approximator) (RatesApproximation, bool) {
 	recDate := record.DateFromDate(date)
+	earlier, later := r.window(recDate, rangeLim)
+	return approximateRates(recDate, earlier, later, approximator)
+}
 
-	// find the nearest earlier and later rate records:
-	earlierRecord, earlierRecordFound := r.nearestEarlierRecord(recDate, rangeLim)
-	laterRecord, laterRecordFound := r.nearestLaterRecord(recDate, rangeLim)
-
-	if !earlierRecordFound && !laterRecordFound {
-		return 0, false
-	}
-
-	if !earlierRecordFound {
-		return laterRecord.Rate(string)
-	}
-
-	if !laterRecordFound {
-		return earlierRecord.Rate(string)
-	}
-
-	earlierRate, foundEarlierRate := earlierRecord.Rate(string)
-	laterRate, foundLaterRate := laterRecord.Rate(string)
-
-	if !foundEarlierRate && !foundLaterRate {
-		return 0, false
-	}
-
-	if !foundEarlierRate {
-		return laterRate, true
-	}
-
-	if !foundLaterRate {
-		return earlierRate, true
-	}
-
-	return (earlierRate + laterRate) / 2, true
+// ApproximateRateWith approximates rate of the given currency on the given date using the given approximator.
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ApproximateRateWith(date date.Date, currency string, rangeLim int, approximator Approximator) (Approximation, bool) {
+	recDate := record.DateFromDate(date)
+	earlier, later := r.window(recDate, rangeLim)
+	return approximateRate(recDate, currency, earlier, later, approximator)
 }
 
 // Convert converts amount from one currency to another on the given date.
-// Operates on O(n) time complexity.
+// Operates on O(log n) time complexity.
 func (r OrderedRecords) Convert(date date.Date, amount float32, from string, to string) (float32, error) {
 	rec, found := r.Rates(date)
 	if !found {
@@ -182,29 +200,21 @@ func (r OrderedRecords) Convert(date date.Date, amount float32, from string, to
 
 // ConvertApproximate converts amount from one currency to another on the given date,
 // using approximated rates within rangeLim days.
-// Operates on O(n) time complexity.
+// Operates on O(log n + rangeLim) time complexity.
 func (r OrderedRecords) ConvertApproximate(date date.Date, amount float32, from string, to string, rangeLim int) (float32, error) {
-	rates, found := r.ApproximateRates(date, rangeLim)
-	if !found {
-		return 0, ErrRateApproximationFailed
-	}
-
-	result, err := rates.Convert(amount, from, to)
-	if err != nil {
-		return 0, err
-	}
-	return result, nil
+	conversion, err := convertApproximateWith(r, date, amount, from, to, rangeLim, Midpoint)
+	return conversion.Amount, err
 }
 
 // ConvertMinors converts amount in minor units from one currency to another on the given date.
-// Operates on O(n) time complexity.
-func (r OrderedRecords) ConvertMinors(date date.Date, amount int64, from string, to string) (int64, error) {
+// Operates on O(log n) time complexity.
+func (r OrderedRecords) ConvertMinors(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error) {
 	rec, found := r.Rates(date)
 	if !found {
 		return 0, ErrRatesRecordNotFound
 	}
 
-	result, err := rec.ConvertMinors(amount, from, to)
+	result, err := rec.ConvertMinors(amount, from, to, opts...)
 	if err != nil {
 		return 0, err
 	}
@@ -214,62 +224,188 @@ func (r OrderedRecords) ConvertMinors(date date.Date, amount int64, from string,
 
 // ConvertMinorsApproximate converts amount in minor units from one currency to another on the given date,
 // using approximated rates within rangeLim days.
-// Operates on O(n) time complexity.
-func (r OrderedRecords) ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int) (int64, error) {
-	rates, found := r.ApproximateRates(date, rangeLim)
-	if !found {
-		return 0, ErrRateApproximationFailed
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	conversion, err := convertMinorsApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
+}
 
+// ConvertDecimal converts amount from one currency to another on the given date using decimal arithmetic.
+// Operates on O(log n) time complexity.
+func (r OrderedRecords) ConvertDecimal(date date.Date, amount decimal.Decimal, from string, to string, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	rec, found := r.Rates(date)
+	if !found {
+		return decimal.Zero, ErrRatesRecordNotFound
 	}
 
-	result, err := rates.ConvertMinors(amount, from, to)
-	if err != nil {
-		return 0, err
+	return rec.ConvertDecimal(amount, from, to, opts...)
+}
+
+// ConvertDecimalApproximate converts amount from one currency to another on the given date
+// using decimal arithmetic and approximated rates within rangeLim days.
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ConvertDecimalApproximate(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	conversion, err := convertDecimalApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
+}
+
+// ConvertMinorsDecimal converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic.
+// Operates on O(log n) time complexity.
+func (r OrderedRecords) ConvertMinorsDecimal(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error) {
+	rec, found := r.Rates(date)
+	if !found {
+		return 0, ErrRatesRecordNotFound
 	}
-	return result, nil
+
+	return rec.ConvertMinorsDecimal(amount, from, to, opts...)
+}
+
+// ConvertMinorsDecimalApproximate converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic and approximated rates within rangeLim days.
+// Operates on O(log n + rangeLim) time complexity.
+func (r OrderedRecords) ConvertMinorsDecimalApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	conversion, err := convertMinorsDecimalApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
 }
 
-// nearestEarlierRecord finds the closest earlier rate record to the given date within the specified range.
+// ConvertApproximateWith converts amount from one currency to another on the given date
+// using rates approximated by the given approximator and reports their provenance.
+func (r OrderedRecords) ConvertApproximateWith(date date.Date, amount float32, from string, to string, rangeLim int, approximator Approximator) (ApproximatedConversion[float32], error) {
+	return convertApproximateWith(r, date, amount, from, to, rangeLim, approximator)
+}
+
+// ConvertMinorsApproximateWith converts amount in minor units from one currency to another on the given date
+// using rates approximated by the given approximator and reports their provenance.
+func (r OrderedRecords) ConvertMinorsApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	return convertMinorsApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// ConvertDecimalApproximateWith converts amount from one currency to another on the given date using decimal
+// arithmetic and rates approximated by the given approximator and reports their provenance.
+func (r OrderedRecords) ConvertDecimalApproximateWith(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[decimal.Decimal], error) {
+	return convertDecimalApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// ConvertMinorsDecimalApproximateWith converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic and rates approximated by the given approximator and reports their provenance.
+func (r OrderedRecords) ConvertMinorsDecimalApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	return convertMinorsDecimalApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// Rebase returns new OrderedRecords with all records rebased onto the given base currency, see [record.Record.Rebase].
+// Records missing rate of the base currency are dropped, their dates are returned in anti-chronological order.
 // Operates on O(n) time complexity.
-func (r OrderedRecords) nearestEarlierRecord(recDate record.Date, rangeLim int) (record.Record, bool) {
-	earlierRecIndex := -1
-	for i, rec := range r {
-		if rec.Date.Before(recDate) {
-			earlierRecIndex = i
-			break
+func (r OrderedRecords) Rebase(base string) (OrderedRecords, []record.Date) {
+	rebased := make(OrderedRecords, 0, len(r))
+	var dropped []record.Date
+	for _, rec := range r {
+		rebasedRec, err := rec.Record.Rebase(base)
+		if err != nil {
+			dropped = append(dropped, rec.Date)
+			continue
 		}
+		rebased = append(rebased, record.NewWithDate(rebasedRec, rec.Date))
 	}
-	if earlierRecIndex == -1 {
-		return nil, false
+	return rebased, dropped
+}
+
+// window returns records within the specified range before and after the given date, the nearest records first.
+// If records around the given date or at the ends of the slice are not in anti-chronological order,
+// sorted copy of the records is used.
+// Operates on O(log n + rangeLim) time complexity if records are sorted.
+func (r OrderedRecords) window(recDate record.Date, rangeLim int) ([]record.WithDate, []record.WithDate) {
+	// earlier records follow the given date in the anti-chronological order:
+	start := r.searchBefore(recDate)
+	end := start
+	for end < len(r) && recDate.SubDays(r[end].Date) <= rangeLim {
+		end++
 	}
+	earlier := r[start:end]
 
-	rec := r[earlierRecIndex]
-	if recDate.SubDays(rec.Date) > rangeLim {
-		return nil, false
+	// later records precede the given date, so they are collected in reverse:
+	var later []record.WithDate
+	i := r.searchNotAfter(recDate) - 1
+	for ; i >= 0 && r[i].Date.SubDays(recDate) <= rangeLim; i-- {
+		later = append(later, r[i])
+	}
+
+	// binary search in unsorted records usually ends up with records which are out of order
+	// around the given date or at the ends of the slice:
+	if len(r) > 0 && (r[0].Date.Before(r[len(r)-1].Date) || !r[max(i, 0):min(end+1, len(r))].IsSorted()) {
+		return r.sortedCopy().window(recDate, rangeLim)
 	}
-	return rec.Record, true
+	return earlier, later
 }
 
-// nearestLaterRecord finds the closest later rate record to the given date within the specified range.
+// currencySpan returns dates of the earliest and the latest records containing rate of the given currency
+// and a boolean indicating whether there are any.
 // Operates on O(n) time complexity.
-func (r OrderedRecords) nearestLaterRecord(recDate record.Date, rangeLim int) (record.Record, bool) {
-	laterRecIndex := -1
-	for i, rec := range r {
-		if rec.Date.After(recDate) {
-			laterRecIndex = i
+func (r OrderedRecords) currencySpan(currency string) (record.Date, record.Date, bool) {
+	var first, last record.Date
+	found := false
+	for _, rec := range r {
+		if _, ok := rec.Record[currency]; !ok {
 			continue
 		}
-		break
+		// records are not necessarily sorted, see OrderedRecords:
+		if !found || rec.Date.Before(first) {
+			first = rec.Date
+		}
+		if !found || rec.Date.After(last) {
+			last = rec.Date
+		}
+		found = true
 	}
+	return first, last, found
+}
 
-	if laterRecIndex == -1 {
-		return nil, false
-	}
+// IsSorted reports whether records are in anti-chronological order, which is required by lookups.
+// Operates on O(n) time complexity.
+func (r OrderedRecords) IsSorted() bool {
+	return sort.SliceIsSorted(r, func(i, j int) bool {
+		return r[i].Date.After(r[j].Date)
+	})
+}
 
-	rec := r[laterRecIndex]
-	if rec.Date.SubDays(recDate) > rangeLim {
-		return nil, false
+// Sort sorts records in anti-chronological order in place.
+// Operates on O(n log n) time complexity.
+func (r OrderedRecords) Sort() {
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Date.After(r[j].Date)
+	})
+}
+
+// sortedCopy returns sorted copy of the records.
+// Operates on O(n log n) time complexity.
+func (r OrderedRecords) sortedCopy() OrderedRecords {
+	sorted := slices.Clone(r)
+	sorted.Sort()
+	return sorted
+}
+
+// sortIfNeeded sorts records if they are not in anti-chronological order.
+// ECB data is already sorted, so usually it only costs O(n) check.
+func (r OrderedRecords) sortIfNeeded() {
+	if !r.IsSorted() {
+		r.Sort()
 	}
+}
+
+// searchNotAfter returns index of the latest record which is not after the given date
+// or len(r) if there is no such record.
+// Operates on O(log n) time complexity.
+func (r OrderedRecords) searchNotAfter(recDate record.Date) int {
+	return sort.Search(len(r), func(i int) bool {
+		return !r[i].Date.After(recDate)
+	})
+}
 
-	return rec.Record, true
+// searchBefore returns index of the latest record which is before the given date
+// or len(r) if there is no such record.
+// Operates on O(log n) time complexity.
+func (r OrderedRecords) searchBefore(recDate record.Date) int {
+	return sort.Search(len(r), func(i int) bool {
+		return r[i].Date.Before(recDate)
+	})
 }
@@ -5,7 +5,10 @@ import (
 	"github.com/jieggii/ecbratex/pkg/xml"
 	"github.com/stretchr/testify/assert"
 	"math"
+	"math/rand/v2"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestNewOrderedRecords(t *testing.T) {
@@ -17,7 +20,7 @@ func TestNewOrderedRecordsFromXML(t *testing.T) {
 	t.Run("valid data", func(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
-				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{"USD", 0.9}}},
+				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
 			},
 		}
 
@@ -27,10 +30,28 @@ func TestNewOrderedRecordsFromXML(t *testing.T) {
 		}
 	})
 
+	t.Run("unsorted data", func(t *testing.T) {
+		data := &xml.Data{
+			Cubes: []xml.DataCube{
+				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
+				{Date: "2024-12-04", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.8}}},
+				{Date: "2024-12-03", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.7}}},
+			},
+		}
+
+		records, err := NewOrderedRecordsFromXML(data)
+		if assert.NoError(t, err) && assert.True(t, records.IsSorted()) {
+			rate, found := records.Rate(record.NewDate(2024, 12, 3), "USD")
+			if assert.True(t, found) {
+				assert.Equal(t, float32(0.7), rate)
+			}
+		}
+	})
+
 	t.Run("data with invalid date", func(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
-				{Date: "invalid date", Rates: []xml.DataCubeRate{{"USD", 0.9}}},
+				{Date: "invalid date", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
 			},
 		}
 		records, err := NewOrderedRecordsFromXML(data)
@@ -264,10 +285,12 @@ func TestOrderedRecords_ApproximateRates(t *testing.T) {
 			record.NewWithDate(record2, record2Date),
 		}
 
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
 		result, found := records.ApproximateRates(record.NewDate(2000, 1, 16), rangeLim)
 		if assert.True(t, found) {
 			assert.Equal(t, (record1["USD"]+record2["USD"])/2, result["USD"])
-			assert.Equal(t, record1["RUB"], result["RUB"])
+			assert.NotContains(t, result, "RUB")
 		}
 	})
 
@@ -287,10 +310,12 @@ func TestOrderedRecords_ApproximateRates(t *testing.T) {
 			record.NewWithDate(record2, record2Date),
 		}
 
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
 		result, found := records.ApproximateRates(record.NewDate(2000, 1, 16), rangeLim)
 		if assert.True(t, found) {
 			assert.Equal(t, (record2["USD"]+record1["USD"])/2, result["USD"])
-			assert.Equal(t, record2["RUB"], result["RUB"])
+			assert.NotContains(t, result, "RUB")
 		}
 	})
 
@@ -345,10 +370,10 @@ func TestOrderedRecords_ApproximateRate(t *testing.T) {
 			}
 		)
 
-		rate, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
-		if assert.True(t, found) {
-			assert.Equal(t, record2["RUB"], rate)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
+		assert.False(t, found)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing rate in the later record", func(t *testing.T) {
@@ -367,10 +392,10 @@ func TestOrderedRecords_ApproximateRate(t *testing.T) {
 			}
 		)
 
-		rate, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
-		if assert.True(t, found) {
-			assert.Equal(t, record1["RUB"], rate)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
+		assert.False(t, found)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing rate in the both records", func(t *testing.T) {
@@ -557,14 +582,11 @@ func TestOrderedRecords_ConvertApproximate(t *testing.T) {
 			}
 		)
 
-		result, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (record1["USD"] + record2["USD"]) / 2
-				rubRate = record1["RUB"]
-			)
-			assert.Equal(t, amount*(usdRate/rubRate), result)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the later record", func(t *testing.T) {
@@ -586,14 +608,11 @@ func TestOrderedRecords_ConvertApproximate(t *testing.T) {
 			}
 		)
 
-		result, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (record1["USD"] + record2["USD"]) / 2
-				rubRate = record2["RUB"]
-			)
-			assert.Equal(t, amount*(usdRate/rubRate), result)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the both records", func(t *testing.T) {
@@ -795,15 +814,11 @@ func TestOrderedRecords_ConvertMinorsApproximate(t *testing.T) {
 			record.NewWithDate(rec1, record.NewDate(2000, 1, 1)),
 		}
 
-		result, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (rec1["USD"] + rec2["USD"]) / 2
-				rubRate = rec2["RUB"]
-			)
-			expectedResult := int64(math.Round(amount * float64(usdRate/rubRate)))
-			assert.Equal(t, expectedResult, result)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the later record", func(t *testing.T) {
@@ -822,15 +837,11 @@ func TestOrderedRecords_ConvertMinorsApproximate(t *testing.T) {
 			record.NewWithDate(rec1, record.NewDate(2000, 1, 1)),
 		}
 
-		result, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (rec1["USD"] + rec2["USD"]) / 2
-				rubRate = rec1["RUB"]
-			)
-			expectedResult := int64(math.Round(amount * float64(usdRate/rubRate)))
-			assert.Equal(t, expectedResult, result)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the both records", func(t *testing.T) {
@@ -927,3 +938,165 @@ func TestOrderedRecords_ConvertMinorsApproximate(t *testing.T) {
 		}
 	})
 }
+
+func TestOrderedRecords_IsSorted(t *testing.T) {
+	var (
+		date1 = record.NewDate(2024, 12, 3)
+		date2 = record.NewDate(2024, 12, 2)
+		date3 = record.NewDate(2024, 12, 1)
+	)
+
+	assert.True(t, OrderedRecords{}.IsSorted())
+	assert.True(t, OrderedRecords{
+		record.NewWithDate(nil, date1),
+		record.NewWithDate(nil, date2),
+		record.NewWithDate(nil, date3),
+	}.IsSorted())
+	assert.False(t, OrderedRecords{
+		record.NewWithDate(nil, date1),
+		record.NewWithDate(nil, date3),
+		record.NewWithDate(nil, date2),
+	}.IsSorted())
+}
+
+func TestOrderedRecords_Sort(t *testing.T) {
+	var (
+		date1 = record.NewDate(2024, 12, 3)
+		date2 = record.NewDate(2024, 12, 2)
+		date3 = record.NewDate(2024, 12, 1)
+	)
+
+	records := OrderedRecords{
+		record.NewWithDate(nil, date3),
+		record.NewWithDate(nil, date1),
+		record.NewWithDate(nil, date2),
+	}
+	records.Sort()
+
+	assert.Equal(t, OrderedRecords{
+		record.NewWithDate(nil, date1),
+		record.NewWithDate(nil, date2),
+		record.NewWithDate(nil, date3),
+	}, records)
+}
+
+func TestOrderedRecords_Unsorted(t *testing.T) {
+	sortedRecords := newSyntheticRecords(1)
+	unorderedRecords := UnorderedRecords(sortedRecords.Map())
+	first, _ := sortedRecords.First()
+	last, _ := sortedRecords.Last()
+	from, to := sortedRecords[len(sortedRecords)/2].Date, sortedRecords[len(sortedRecords)/4].Date
+
+	chronologicalRecords := slices.Clone(sortedRecords)
+	slices.Reverse(chronologicalRecords)
+
+	shuffledRecords := slices.Clone(sortedRecords)
+	rand.New(rand.NewPCG(3, 4)).Shuffle(len(shuffledRecords), func(i, j int) {
+		shuffledRecords[i], shuffledRecords[j] = shuffledRecords[j], shuffledRecords[i]
+	})
+
+	t.Run("chronological", func(t *testing.T) {
+		records := slices.Clone(chronologicalRecords)
+		assert.False(t, records.IsSorted())
+
+		for day := first.Date.AddDays(-10); !day.After(last.Date.AddDays(10)); day = day.AddDays(1) {
+			rates, found := records.Rates(day)
+			expectedRates, expectedFound := unorderedRecords.Rates(day)
+			assert.Equal(t, expectedFound, found, day.String())
+			assert.Equal(t, expectedRates, rates, day.String())
+
+			approximation, ok := records.ApproximateRatesWith(day, 3, Linear)
+			expectedApproximation, expectedOk := unorderedRecords.ApproximateRatesWith(day, 3, Linear)
+			assert.Equal(t, expectedOk, ok, day.String())
+			assert.Equal(t, expectedApproximation, approximation, day.String())
+		}
+		assert.Equal(t, sortedRecords.Range(from, to), records.Range(from, to))
+		assert.Equal(t, chronologicalRecords, records, "records must not be modified")
+	})
+
+	t.Run("shuffled", func(t *testing.T) {
+		records := slices.Clone(shuffledRecords)
+		assert.False(t, records.IsSorted())
+
+		for _, rec := range sortedRecords {
+			rates, found := records.Rates(rec.Date)
+			if assert.True(t, found, rec.Date.String()) {
+				assert.Equal(t, rec.Record, rates, rec.Date.String())
+			}
+		}
+		_, found := records.Rates(last.Date.AddDays(1))
+		assert.False(t, found)
+		assert.Equal(t, sortedRecords.Range(from, to), records.Range(from, to))
+		assert.Equal(t, shuffledRecords, records, "records must not be modified")
+
+		records.Sort()
+		assert.True(t, records.IsSorted())
+		assert.Equal(t, sortedRecords, records)
+	})
+}
+
+// newSyntheticRecords generates records of every business day of the given number of years starting from
+// [record.MinDate] in anti-chronological order, similar to the ECB historical data.
+func newSyntheticRecords(years int) OrderedRecords {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	start := record.MinDate.Time()
+	end := start.AddDate(years, 0, 0)
+
+	records := NewOrderedRecords()
+	for day := end; !day.Before(start); day = day.AddDate(0, 0, -1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		rec := record.Record{"EUR": 1, "USD": 1 + rng.Float32(), "JPY": 100 + 100*rng.Float32()}
+		records = append(records, record.NewWithDate(rec, record.DateFromTime(day)))
+	}
+	return records
+}
+
+func TestOrderedRecords_BinarySearch(t *testing.T) {
+	// binary search lookups must give the same results as UnorderedRecords, which uses map lookups:
+	records := newSyntheticRecords(2)
+	unorderedRecords := UnorderedRecords(records.Map())
+
+	first, _ := records.First()
+	last, _ := records.Last()
+	for day := first.Date.AddDays(-10); !day.After(last.Date.AddDays(10)); day = day.AddDays(1) {
+		rates, found := records.Rates(day)
+		expectedRates, expectedFound := unorderedRecords.Rates(day)
+		assert.Equal(t, expectedFound, found, day.String())
+		assert.Equal(t, expectedRates, rates, day.String())
+
+		for _, rangeLim := range []int{1, 3, DefaultRangeLim} {
+			rates, found = records.ApproximateRates(day, rangeLim)
+			expectedRates, expectedFound = unorderedRecords.ApproximateRates(day, rangeLim)
+			assert.Equal(t, expectedFound, found, day.String())
+			assert.Equal(t, expectedRates, rates, day.String())
+		}
+	}
+}
+
+func BenchmarkOrderedRecords_Rates(b *testing.B) {
+	records := newSyntheticRecords(25)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// query records from all over the history, so that linear scan would take O(n) on average:
+		recDate := records[(i*7919)%len(records)].Date
+		if _, found := records.Rates(recDate); !found {
+			b.Fatal("rates not found")
+		}
+	}
+}
+
+func BenchmarkOrderedRecords_ApproximateRates(b *testing.B) {
+	records := newSyntheticRecords(25)
+	saturday := record.NewDate(2012, 6, 16)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, found := records.ApproximateRates(saturday, DefaultRangeLim); !found {
+			b.Fatal("rates not approximated")
+		}
+	}
+}
@@ -1,14 +1,19 @@
 package timeseries
 
 import (
+	"github.com/jieggii/ecbratex/pkg/date"
 	"github.com/jieggii/ecbratex/pkg/record"
 	"github.com/jieggii/ecbratex/pkg/xml"
+	"io"
+	"slices"
+	"sort"
 )
 
 // OrderedUnorderedRecords is an implementation of the Records interface.
 // It combines benefits of UnorderedRecords and OrderedRecords, but takes more memory.
 type OrderedUnorderedRecords struct {
 	// Dates of all rate records in anti-chronological order.
+	// Range uses binary search, unsorted dates are sorted in a copy first.
 	Dates []record.Date
 
 	// UnorderedRecords is the underlying data structure to store rate records.
@@ -17,27 +22,62 @@ type OrderedUnorderedRecords struct {
 
 // NewOrderedUnorderedRecordsFromXML creates a new OrderedUnorderedRecords from [xml.Data].
 func NewOrderedUnorderedRecordsFromXML(xmlData *xml.Data) (*OrderedUnorderedRecords, error) {
-	rates := make(UnorderedRecords)
-	dates := make([]record.Date, 0)
-
+	records := &OrderedUnorderedRecords{
+		Dates:            make([]record.Date, 0, len(xmlData.Cubes)),
+		UnorderedRecords: make(UnorderedRecords, len(xmlData.Cubes)),
+	}
 	for _, cube := range xmlData.Cubes {
-		date, err := record.DateFromString(cube.Date)
-		if err != nil {
+		if err := records.addCube(cube); err != nil {
 			return nil, err
 		}
-		dates = append(dates, date)
+	}
+	records.sortDatesIfNeeded()
 
-		rates[date] = record.New()
-		for _, rate := range cube.Rates {
-			rates[date][rate.Currency] = rate.Rate
-		}
-		rates[date]["EUR"] = 1 // add EUR rate for convenience
+	return records, nil
+}
+
+// NewOrderedUnorderedRecordsFromReader creates a new OrderedUnorderedRecords from XML document read from r.
+// Unlike NewOrderedUnorderedRecordsFromXML, it does not keep the whole decoded document in memory,
+// see [xml.DecodeCubes].
+func NewOrderedUnorderedRecordsFromReader(r io.Reader) (*OrderedUnorderedRecords, error) {
+	records := &OrderedUnorderedRecords{
+		Dates:            make([]record.Date, 0),
+		UnorderedRecords: make(UnorderedRecords),
+	}
+	if err := xml.DecodeCubes(r, records.addCube); err != nil {
+		return nil, err
 	}
+	records.sortDatesIfNeeded()
 
-	return &OrderedUnorderedRecords{
-		Dates:            dates,
-		UnorderedRecords: rates,
-	}, nil
+	return records, nil
+}
+
+// addCube appends record created from the given cube.
+func (r *OrderedUnorderedRecords) addCube(cube xml.DataCube) error {
+	recDate, rec, err := newRecordFromCube(cube)
+	if err != nil {
+		return err
+	}
+	r.Dates = append(r.Dates, recDate)
+	r.UnorderedRecords[recDate] = rec
+	return nil
+}
+
+// sortDatesIfNeeded sorts dates in anti-chronological order if they are not sorted yet.
+func (r *OrderedUnorderedRecords) sortDatesIfNeeded() {
+	if !r.datesSorted() {
+		sort.SliceStable(r.Dates, func(i, j int) bool {
+			return r.Dates[i].After(r.Dates[j])
+		})
+	}
+}
+
+// datesSorted reports whether dates are in anti-chronological order.
+// Operates on O(n) time complexity.
+func (r OrderedUnorderedRecords) datesSorted() bool {
+	return sort.SliceIsSorted(r.Dates, func(i, j int) bool {
+		return r.Dates[i].After(r.Dates[j])
+	})
 }
 
 // Slice returns the underlying slice containing all records in anti-chronological order.
@@ -50,3 +90,82 @@ func (r OrderedUnorderedRecords) Slice() []record.WithDate {
 	}
 	return records
 }
+
+// Len returns number of records.
+// Operates on O(1) time complexity.
+func (r OrderedUnorderedRecords) Len() int {
+	return len(r.Dates)
+}
+
+// First returns the earliest record and a boolean indicating whether there are any records.
+// Operates on O(1) time complexity.
+func (r OrderedUnorderedRecords) First() (record.WithDate, bool) {
+	if len(r.Dates) == 0 {
+		return record.WithDate{}, false
+	}
+	d := r.Dates[len(r.Dates)-1]
+	return record.NewWithDate(r.UnorderedRecords[d], d), true
+}
+
+// Last returns the latest record and a boolean indicating whether there are any records.
+// Operates on O(1) time complexity.
+func (r OrderedUnorderedRecords) Last() (record.WithDate, bool) {
+	if len(r.Dates) == 0 {
+		return record.WithDate{}, false
+	}
+	d := r.Dates[0]
+	return record.NewWithDate(r.UnorderedRecords[d], d), true
+}
+
+// Range returns *OrderedUnorderedRecords containing records dated within the [from, to] interval.
+// Operates on O(n) time complexity to check that dates are sorted.
+func (r OrderedUnorderedRecords) Range(from date.Date, to date.Date) Records {
+	if !r.datesSorted() {
+		sorted := OrderedUnorderedRecords{Dates: slices.Clone(r.Dates), UnorderedRecords: r.UnorderedRecords}
+		sorted.sortDatesIfNeeded()
+		return sorted.Range(from, to)
+	}
+
+	fromDate, toDate := record.DateFromDate(from), record.DateFromDate(to)
+
+	// dates are in anti-chronological order, see OrderedRecords.Range:
+	start := sort.Search(len(r.Dates), func(i int) bool {
+		return !r.Dates[i].After(toDate)
+	})
+	end := sort.Search(len(r.Dates), func(i int) bool {
+		return r.Dates[i].Before(fromDate)
+	})
+
+	result := &OrderedUnorderedRecords{
+		Dates:            make([]record.Date, 0, max(end-start, 0)),
+		UnorderedRecords: make(UnorderedRecords, max(end-start, 0)),
+	}
+	for i := start; i < end; i++ {
+		d := r.Dates[i]
+		result.Dates = append(result.Dates, d)
+		result.UnorderedRecords[d] = r.UnorderedRecords[d]
+	}
+	return result
+}
+
+// Rebase returns new OrderedUnorderedRecords with all records rebased onto the given base currency,
+// see [record.Record.Rebase].
+// Records missing rate of the base currency are dropped, their dates are returned in anti-chronological order.
+// Operates on O(n) time complexity.
+func (r OrderedUnorderedRecords) Rebase(base string) (*OrderedUnorderedRecords, []record.Date) {
+	rebased := &OrderedUnorderedRecords{
+		Dates:            make([]record.Date, 0, len(r.Dates)),
+		UnorderedRecords: make(UnorderedRecords, len(r.UnorderedRecords)),
+	}
+	var dropped []record.Date
+	for _, recDate := range r.Dates {
+		rebasedRec, err := r.UnorderedRecords[recDate].Rebase(base)
+		if err != nil {
+			dropped = append(dropped, recDate)
+			continue
+		}
+		rebased.Dates = append(rebased.Dates, recDate)
+		rebased.UnorderedRecords[recDate] = rebasedRec
+	}
+	return rebased, dropped
+}
@@ -11,7 +11,7 @@ func TestNewOrderedUnorderedRecordsFromXML(t *testing.T) {
 	t.Run("valid data", func(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
-				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{"USD", 0.9}}},
+				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
 			},
 		}
 
@@ -24,7 +24,7 @@ func TestNewOrderedUnorderedRecordsFromXML(t *testing.T) {
 	t.Run("data with invalid date", func(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
-				{Date: "invalid date", Rates: []xml.DataCubeRate{{"USD", 0.9}}},
+				{Date: "invalid date", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
 			},
 		}
 		records, err := NewOrderedUnorderedRecordsFromXML(data)
@@ -39,6 +39,25 @@ func TestNewOrderedUnorderedRecordsFromXML(t *testing.T) {
 		})
 	})
 
+	t.Run("unsorted data", func(t *testing.T) {
+		data := &xml.Data{
+			Cubes: []xml.DataCube{
+				{Date: "2024-12-02", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.9}}},
+				{Date: "2024-12-04", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.8}}},
+				{Date: "2024-12-03", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 0.7}}},
+			},
+		}
+
+		records, err := NewOrderedUnorderedRecordsFromXML(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []record.Date{
+				record.NewDate(2024, 12, 4),
+				record.NewDate(2024, 12, 3),
+				record.NewDate(2024, 12, 2),
+			}, records.Dates)
+		}
+	})
+
 	t.Run("empty data", func(t *testing.T) {
 		data := &xml.Data{}
 		records, err := NewOrderedUnorderedRecordsFromXML(data)
@@ -79,3 +98,25 @@ func TestOrderedUnorderedRecords_Slice(t *testing.T) {
 		records.Slice(),
 	)
 }
+
+func TestOrderedUnorderedRecords_UnsortedRange(t *testing.T) {
+	var (
+		date1 = record.NewDate(2000, 1, 3)
+		date2 = record.NewDate(2000, 1, 2)
+		date3 = record.NewDate(2000, 1, 1)
+	)
+
+	records := OrderedUnorderedRecords{
+		Dates: []record.Date{date3, date1, date2},
+		UnorderedRecords: UnorderedRecords{
+			date1: record.Record{"USD": 0.7},
+			date2: record.Record{"USD": 0.8},
+			date3: record.Record{"USD": 0.9},
+		},
+	}
+
+	result := records.Range(date2, date1).(*OrderedUnorderedRecords)
+	assert.Equal(t, []record.Date{date1, date2}, result.Dates)
+	assert.Equal(t, UnorderedRecords{date1: records.UnorderedRecords[date1], date2: records.UnorderedRecords[date2]}, result.UnorderedRecords)
+	assert.Equal(t, []record.Date{date3, date1, date2}, records.Dates, "dates must not be modified")
+}
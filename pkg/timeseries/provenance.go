@@ -0,0 +1,183 @@
+package timeseries
+
+import (
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/date"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"sort"
+)
+
+// Provenance describes where an approximated value comes from, so that it can be stored next to the value for audit.
+type Provenance struct {
+	// Method is name of the approximator used, see [Approximator].
+	Method string
+
+	// Earlier is date of the earliest source record before the approximated date
+	// or [record.ZeroDate] if no earlier record was used.
+	Earlier record.Date
+
+	// Later is date of the latest source record after the approximated date
+	// or [record.ZeroDate] if no later record was used.
+	Later record.Date
+
+	// Distance is number of days between the approximated date and the farthest source record.
+	Distance int
+}
+
+// HasEarlier reports whether an earlier record was used.
+func (p Provenance) HasEarlier() bool {
+	return p.Earlier != record.ZeroDate
+}
+
+// HasLater reports whether a later record was used.
+func (p Provenance) HasLater() bool {
+	return p.Later != record.ZeroDate
+}
+
+// newProvenance creates provenance of a value approximated on the given date using the given method and source dates.
+func newProvenance(recDate record.Date, method string, sources []record.Date) Provenance {
+	provenance := Provenance{Method: method}
+	for _, source := range sources {
+		switch {
+		case source.Before(recDate):
+			if !provenance.HasEarlier() || source.Before(provenance.Earlier) {
+				provenance.Earlier = source
+			}
+			provenance.Distance = max(provenance.Distance, recDate.SubDays(source))
+		case source.After(recDate):
+			if !provenance.HasLater() || source.After(provenance.Later) {
+				provenance.Later = source
+			}
+			provenance.Distance = max(provenance.Distance, source.SubDays(recDate))
+		}
+	}
+	return provenance
+}
+
+// missingRateError returns error explaining why rate of the currency could not be approximated on the given date.
+// It wraps ErrRateApproximationFailed and, if the reason is known, one of ErrCurrencyDiscontinued,
+// ErrCurrencyNotYetPublished, ErrCurrencyTemporarilyAbsent or [record.ErrRateNotFound].
+// Operates on O(n) time complexity, so it is only called once approximation failed.
+func missingRateError(records approximationSource, recDate record.Date, currency string, rangeLim int) error {
+	earlier, later := records.window(recDate, rangeLim)
+	if _, _, ok := nearestObservations(earlier, later, currency); ok {
+		// the rate was observed, but the approximator could not use it:
+		return fmt.Errorf("approximate %s rate: %w", currency, ErrRateApproximationFailed)
+	}
+
+	// a currency published in the earliest (latest) record may be published before (after) the time series,
+	// so it is not known whether it is published on dates out of the time series:
+	first, _ := records.First()
+	last, _ := records.Last()
+
+	var reason error
+	firstSeen, lastSeen, found := records.currencySpan(currency)
+	switch {
+	case !found:
+		reason = record.ErrRateNotFound
+	case lastSeen.Before(recDate) && lastSeen != last.Date:
+		reason = ErrCurrencyDiscontinued
+	case firstSeen.After(recDate) && firstSeen != first.Date:
+		reason = ErrCurrencyNotYetPublished
+	case firstSeen.Before(recDate) && lastSeen.After(recDate):
+		reason = ErrCurrencyTemporarilyAbsent
+	default:
+		return fmt.Errorf("approximate %s rate: %w", currency, ErrRateApproximationFailed)
+	}
+	return fmt.Errorf("approximate %s rate: %w: %w", currency, ErrRateApproximationFailed, reason)
+}
+
+// ApproximatedConversion is result of conversion using approximated rates.
+type ApproximatedConversion[T float32 | int64 | decimal.Decimal] struct {
+	// Amount is the converted amount.
+	Amount T
+
+	// Sources are dates of records rates of both currencies were derived from in chronological order.
+	Sources []record.Date
+
+	Provenance
+}
+
+// approximateConversionRates approximates rates of the given currencies on the given date and returns them
+// as a record together with their source dates and provenance.
+func approximateConversionRates(records approximationSource, date date.Date, from string, to string, rangeLim int, approximator Approximator) (record.Record, []record.Date, Provenance, error) {
+	rates := make(record.Record, 2)
+	sourcesSet := make(map[record.Date]struct{}, 2)
+	for _, currency := range []string{from, to} {
+		approximation, ok := records.ApproximateRateWith(date, currency, rangeLim, approximator)
+		if !ok {
+			return nil, nil, Provenance{}, missingRateError(records, record.DateFromDate(date), currency, rangeLim)
+		}
+		rates[currency] = approximation.Rate
+		for _, source := range approximation.Sources {
+			sourcesSet[source] = struct{}{}
+		}
+	}
+
+	sources := make([]record.Date, 0, len(sourcesSet))
+	for source := range sourcesSet {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Before(sources[j])
+	})
+
+	return rates, sources, newProvenance(record.DateFromDate(date), approximator.Name, sources), nil
+}
+
+// convertApproximateWith implements Records.ConvertApproximateWith.
+func convertApproximateWith(records approximationSource, date date.Date, amount float32, from string, to string, rangeLim int, approximator Approximator) (ApproximatedConversion[float32], error) {
+	rates, sources, provenance, err := approximateConversionRates(records, date, from, to, rangeLim, approximator)
+	if err != nil {
+		return ApproximatedConversion[float32]{}, err
+	}
+
+	result, err := rates.Convert(amount, from, to)
+	if err != nil {
+		return ApproximatedConversion[float32]{}, err
+	}
+	return ApproximatedConversion[float32]{Amount: result, Sources: sources, Provenance: provenance}, nil
+}
+
+// convertMinorsApproximateWith implements Records.ConvertMinorsApproximateWith.
+func convertMinorsApproximateWith(records approximationSource, date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts []record.ConvertOption) (ApproximatedConversion[int64], error) {
+	rates, sources, provenance, err := approximateConversionRates(records, date, from, to, rangeLim, approximator)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+
+	result, err := rates.ConvertMinors(amount, from, to, opts...)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+	return ApproximatedConversion[int64]{Amount: result, Sources: sources, Provenance: provenance}, nil
+}
+
+// convertDecimalApproximateWith implements Records.ConvertDecimalApproximateWith.
+func convertDecimalApproximateWith(records approximationSource, date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, approximator Approximator, opts []record.ConvertOption) (ApproximatedConversion[decimal.Decimal], error) {
+	rates, sources, provenance, err := approximateConversionRates(records, date, from, to, rangeLim, approximator)
+	if err != nil {
+		return ApproximatedConversion[decimal.Decimal]{}, err
+	}
+
+	result, err := rates.ConvertDecimal(amount, from, to, opts...)
+	if err != nil {
+		return ApproximatedConversion[decimal.Decimal]{}, err
+	}
+	return ApproximatedConversion[decimal.Decimal]{Amount: result, Sources: sources, Provenance: provenance}, nil
+}
+
+// convertMinorsDecimalApproximateWith implements Records.ConvertMinorsDecimalApproximateWith.
+func convertMinorsDecimalApproximateWith(records approximationSource, date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts []record.ConvertOption) (ApproximatedConversion[int64], error) {
+	rates, sources, provenance, err := approximateConversionRates(records, date, from, to, rangeLim, approximator)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+
+	result, err := rates.ConvertMinorsDecimal(amount, from, to, opts...)
+	if err != nil {
+		return ApproximatedConversion[int64]{}, err
+	}
+	return ApproximatedConversion[int64]{Amount: result, Sources: sources, Provenance: provenance}, nil
+}
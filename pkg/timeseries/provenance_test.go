@@ -0,0 +1,41 @@
+package timeseries
+
+import (
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewProvenance(t *testing.T) {
+	date := record.NewDate(2024, 2, 24)
+
+	t.Run("both sides", func(t *testing.T) {
+		provenance := newProvenance(date, "linear", []record.Date{
+			record.NewDate(2024, 2, 20),
+			record.NewDate(2024, 2, 23),
+			record.NewDate(2024, 2, 26),
+		})
+		assert.Equal(t, Provenance{
+			Method:   "linear",
+			Earlier:  record.NewDate(2024, 2, 20),
+			Later:    record.NewDate(2024, 2, 26),
+			Distance: 4,
+		}, provenance)
+		assert.True(t, provenance.HasEarlier())
+		assert.True(t, provenance.HasLater())
+	})
+
+	t.Run("later side only", func(t *testing.T) {
+		provenance := newProvenance(date, "next-available", []record.Date{record.NewDate(2024, 2, 26)})
+		assert.Equal(t, Provenance{Method: "next-available", Later: record.NewDate(2024, 2, 26), Distance: 2}, provenance)
+		assert.False(t, provenance.HasEarlier())
+		assert.True(t, provenance.HasLater())
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		provenance := newProvenance(date, "midpoint", nil)
+		assert.Equal(t, Provenance{Method: "midpoint"}, provenance)
+		assert.False(t, provenance.HasEarlier())
+		assert.False(t, provenance.HasLater())
+	})
+}
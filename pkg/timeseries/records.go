@@ -3,12 +3,25 @@ package timeseries
 import (
 	"errors"
 	"github.com/jieggii/ecbratex/pkg/date"
+	"github.com/jieggii/ecbratex/pkg/decimal"
 	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/xml"
 )
 
 var (
 	ErrRatesRecordNotFound     = errors.New("exchange rates record was not found on the given date")
 	ErrRateApproximationFailed = errors.New("approximation of one or multiple exchange rates did not succeed within the given date and range limit")
+
+	// ErrCurrencyDiscontinued error indicates that rates of the currency are not published after the given date anymore
+	// (for example, HRK after 2022 or RUB since March 2022).
+	ErrCurrencyDiscontinued = errors.New("currency was discontinued before the given date")
+
+	// ErrCurrencyNotYetPublished error indicates that rates of the currency were first published after the given date.
+	ErrCurrencyNotYetPublished = errors.New("currency was not published yet on the given date")
+
+	// ErrCurrencyTemporarilyAbsent error indicates that rates of the currency are published before and after the given date,
+	// but not on both sides of it within the range limit.
+	ErrCurrencyTemporarilyAbsent = errors.New("currency is temporarily absent around the given date")
 )
 
 // DefaultRangeLim is a sane default for rangeLim argument passed to some functions of Records.
@@ -27,6 +40,19 @@ type Records interface {
 	// Map returns map containing all exchange rates records indexed by their date.
 	Map() map[record.Date]record.Record
 
+	// Len returns number of records.
+	Len() int
+
+	// First returns the earliest record and a boolean indicating whether there are any records.
+	First() (record.WithDate, bool)
+
+	// Last returns the latest record and a boolean indicating whether there are any records.
+	Last() (record.WithDate, bool)
+
+	// Range returns records dated within the [from, to] interval (both ends inclusive).
+	// The result has the same underlying type as the records it was called on.
+	Range(from date.Date, to date.Date) Records
+
 	// Rates retrieves currency rates for the given date.
 	// It returns the rates record for the specified date and a boolean indicating whether the record was found.
 	Rates(date date.Date) (record.Record, bool)
@@ -36,23 +62,42 @@ type Records interface {
 	Rate(date date.Date, currency string) (float32, bool)
 
 	// ApproximateRates calculates approximate rates for the given date within a specified days range.
-	// It searches for the nearest earlier and later rate records within the given range (rangeLim).
+	// For each currency, it searches for the nearest earlier and later rate records containing the currency
+	// within the given range (rangeLim), so rates missing from the nearest records are taken from farther ones.
 	// If neither earlier nor later rate records are found within the range limit, it returns false.
-	// If only one of either earlier or later rate records is found, it returns the rates of that record.
-	// If both earlier and later rate records are found, it approximates rates by interpolating between
-	// the rates of the closest earlier and later records, or using the rates.
+	// If both earlier and later rates are found, it approximates the rate by taking their average, see [Midpoint].
+	// If only one of either earlier or later rates is found, it is used as is only if there are no records at all
+	// on the other side within the range limit (for example, after the latest record). Otherwise, the currency
+	// is not published on the given date (it is discontinued, not yet published or temporarily absent),
+	// so it is left out of the result.
 	// Is useful when there is no rates record on the desired date.
 	ApproximateRates(date date.Date, rangeLim int) (record.Record, bool)
 
 	// ApproximateRate calculates an approximate rate for the given currency on a given date within a specified days range.
-	// It searches for the closest earlier and later rate records within the given range (rangeLim).
+	// It searches for the closest earlier and later rate records containing the currency within the given range (rangeLim).
 	// If neither earlier nor later rate records are found within the range limit, it returns false.
-	// If only one of either earlier or later rate records is found, it returns the rate of that record.
-	// If both earlier and later rate records are found, it approximates the rate by interpolating between
-	// the rates of the closest earlier and later records, or using the rates if interpolation is not possible.
+	// If both earlier and later rate records are found, it approximates the rate by taking the average of
+	// their rates, see [Midpoint].
+	// If only one of either earlier or later rate records is found, it returns the rate of that record only if
+	// there are no records at all on the other side within the range limit. Otherwise, the currency is not
+	// published on the given date (see ConvertApproximateWith for the reasons), so it returns false.
 	// Is useful when there is no rates record on the desired date.
 	ApproximateRate(date date.Date, currency string, rangeLim int) (float32, bool)
 
+	// ApproximateRatesWith approximates rates on the given date using the given approximator, see [Approximator].
+	// It approximates rate of each currency found in any record within the given range (rangeLim) using the nearest
+	// earlier and later records containing the currency. It returns false if no rate could be approximated.
+	// Currencies which are not published on the given date are left out, see ApproximateRates.
+	// Unlike ApproximateRates, it also reports dates of the records the rates were derived from.
+	ApproximateRatesWith(date date.Date, rangeLim int, approximator Approximator) (RatesApproximation, bool)
+
+	// ApproximateRateWith approximates rate of the given currency on the given date using the given approximator,
+	// see [Approximator]. It searches for the nearest earlier and later rate records containing the currency
+	// within the given range (rangeLim). It returns false if the rate could not be approximated
+	// or the currency is not published on the given date, see ApproximateRate.
+	// Unlike ApproximateRate, it also reports dates of the records the rate was derived from.
+	ApproximateRateWith(date date.Date, currency string, rangeLim int, approximator Approximator) (Approximation, bool)
+
 	// Convert converts the specified amount from one currency to another on the given date.
 	// It returns the converted amount as a float32 or an error if conversion fails.
 	Convert(date date.Date, amount float32, from string, to string) (float32, error)
@@ -60,14 +105,85 @@ type Records interface {
 	// ConvertApproximate converts the specified amount of currency from one currency to another on the given date
 	// using approximate rates within a specified days determined by rangeLim.
 	// It returns the converted amount as a float32 or an error if conversion fails.
+	// If a rate could not be approximated, the error explains why, see ConvertApproximateWith.
 	ConvertApproximate(date date.Date, amount float32, from string, to string, rangeLim int) (float32, error)
 
 	// ConvertMinors converts the specified amount of currency in minor units from one currency to another on the given date.
+	// The result is rounded using the rounding mode set by [record.WithRounding].
 	// It returns the converted amount as an int or an error if conversion fails.
-	ConvertMinors(date date.Date, amount int64, from string, to string) (int64, error)
+	ConvertMinors(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error)
 
 	// ConvertMinorsApproximate converts the specified amount of currency in minor units from one currency to another on the given date
 	// using approximate rates within a specified days determined by rangeLim.
 	// It returns the converted amount as an int or an error if conversion fails.
-	ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int) (int64, error)
+	ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error)
+
+	// ConvertDecimal converts the specified amount from one currency to another on the given date
+	// using decimal arithmetic, see [record.Record.ConvertDecimal].
+	// It returns the converted amount or an error if conversion fails.
+	ConvertDecimal(date date.Date, amount decimal.Decimal, from string, to string, opts ...record.ConvertOption) (decimal.Decimal, error)
+
+	// ConvertDecimalApproximate converts the specified amount from one currency to another on the given date
+	// using decimal arithmetic and approximate rates within a specified days determined by rangeLim.
+	// It returns the converted amount or an error if conversion fails.
+	ConvertDecimalApproximate(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, opts ...record.ConvertOption) (decimal.Decimal, error)
+
+	// ConvertMinorsDecimal converts the specified amount of currency in minor units from one currency to another
+	// on the given date using decimal arithmetic, see [record.Record.ConvertMinorsDecimal].
+	// It returns the converted amount as an int or an error if conversion fails.
+	ConvertMinorsDecimal(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error)
+
+	// ConvertMinorsDecimalApproximate converts the specified amount of currency in minor units from one currency
+	// to another on the given date using decimal arithmetic and approximate rates within a specified days
+	// determined by rangeLim.
+	// It returns the converted amount as an int or an error if conversion fails.
+	ConvertMinorsDecimalApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error)
+
+	// ConvertApproximateWith converts the specified amount from one currency to another on the given date
+	// using rates of both currencies approximated by the given approximator within a specified days range (rangeLim).
+	// Unlike ConvertApproximate, it also reports dates of the records the rates were derived from, see [Provenance].
+	// If a rate could not be approximated, the returned error wraps ErrRateApproximationFailed and, if the reason
+	// is known, ErrCurrencyDiscontinued, ErrCurrencyNotYetPublished, ErrCurrencyTemporarilyAbsent
+	// or [record.ErrRateNotFound].
+	ConvertApproximateWith(date date.Date, amount float32, from string, to string, rangeLim int, approximator Approximator) (ApproximatedConversion[float32], error)
+
+	// ConvertMinorsApproximateWith is like ConvertApproximateWith, but converts amount in minor units,
+	// see ConvertMinors.
+	ConvertMinorsApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error)
+
+	// ConvertDecimalApproximateWith is like ConvertApproximateWith, but uses decimal arithmetic, see ConvertDecimal.
+	ConvertDecimalApproximateWith(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[decimal.Decimal], error)
+
+	// ConvertMinorsDecimalApproximateWith is like ConvertApproximateWith, but converts amount in minor units
+	// using decimal arithmetic, see ConvertMinorsDecimal.
+	ConvertMinorsDecimalApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error)
+}
+
+// approximationSource is implemented by all Records implementations of this package
+// to share approximation code between them.
+type approximationSource interface {
+	Records
+
+	// window returns records within the specified range before and after the given date, the nearest records first.
+	window(recDate record.Date, rangeLim int) ([]record.WithDate, []record.WithDate)
+
+	// currencySpan returns dates of the earliest and the latest records containing rate of the given currency
+	// and a boolean indicating whether there are any.
+	currencySpan(currency string) (record.Date, record.Date, bool)
+}
+
+// newRecordFromCube creates a new record from the given cube and parses its date.
+func newRecordFromCube(cube xml.DataCube) (record.Date, record.Record, error) {
+	recDate, err := record.DateFromString(cube.Date)
+	if err != nil {
+		return record.Date{}, nil, err
+	}
+
+	rec := make(record.Record, len(cube.Rates)+1)
+	for _, rate := range cube.Rates {
+		rec[rate.Currency] = rate.Rate
+	}
+	rec["EUR"] = 1 // add EUR rate for convenience
+
+	return recDate, rec, nil
 }
@@ -0,0 +1,923 @@
+package timeseries
+
+import (
+	"bytes"
+	"github.com/jieggii/ecbratex/pkg/currency"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/rounding"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testHistXMLFilePath = "./../../testdata/eurofxref-hist.xml"
+
+func readHistXML(tb testing.TB) []byte {
+	tb.Helper()
+
+	data, err := os.ReadFile(testHistXMLFilePath)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+func TestNewRecordsFromReader(t *testing.T) {
+	data := readHistXML(t)
+	xmlData, err := xml.NewData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("OrderedRecords", func(t *testing.T) {
+		expected, err := NewOrderedRecordsFromXML(xmlData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := NewOrderedRecordsFromReader(bytes.NewReader(data))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, records)
+		}
+	})
+
+	t.Run("UnorderedRecords", func(t *testing.T) {
+		expected, err := NewUnorderedRecordsFromXML(xmlData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := NewUnorderedRecordsFromReader(bytes.NewReader(data))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, records)
+		}
+	})
+
+	t.Run("OrderedUnorderedRecords", func(t *testing.T) {
+		expected, err := NewOrderedUnorderedRecordsFromXML(xmlData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := NewOrderedUnorderedRecordsFromReader(bytes.NewReader(data))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, records)
+		}
+	})
+
+	t.Run("data with invalid date", func(t *testing.T) {
+		const data = `<gesmes:Envelope xmlns:gesmes="` + xml.GesmesNamespace + `" xmlns="` + xml.EurofxrefNamespace + `">` +
+			`<gesmes:subject>Reference rates</gesmes:subject>` +
+			`<Cube><Cube time="invalid date"><Cube currency="USD" rate="0.9"/></Cube></Cube></gesmes:Envelope>`
+
+		ordered, err := NewOrderedRecordsFromReader(strings.NewReader(data))
+		assert.ErrorContains(t, err, "invalid date")
+		assert.Nil(t, ordered)
+
+		unordered, err := NewUnorderedRecordsFromReader(strings.NewReader(data))
+		assert.Error(t, err)
+		assert.Nil(t, unordered)
+
+		orderedUnordered, err := NewOrderedUnorderedRecordsFromReader(strings.NewReader(data))
+		assert.Error(t, err)
+		assert.Nil(t, orderedUnordered)
+	})
+}
+
+func BenchmarkNewUnorderedRecords(b *testing.B) {
+	data := readHistXML(b)
+
+	b.Run("FromXML", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			xmlData, err := xml.NewData(data)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := NewUnorderedRecordsFromXML(xmlData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("FromReader", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewUnorderedRecordsFromReader(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNewOrderedRecords(b *testing.B) {
+	data := readHistXML(b)
+
+	b.Run("FromXML", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			xmlData, err := xml.NewData(data)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := NewOrderedRecordsFromXML(xmlData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("FromReader", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewOrderedRecordsFromReader(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestRecords_ConvertDecimal(t *testing.T) {
+	var (
+		earlierDate = record.NewDate(2024, 2, 26)
+		laterDate   = record.NewDate(2024, 2, 28)
+		earlierRec  = record.Record{"EUR": 1, "USD": 1.08, "JPY": 162}
+		laterRec    = record.Record{"EUR": 1, "USD": 1.09, "JPY": 164}
+	)
+
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(laterRec, laterDate),
+			record.NewWithDate(earlierRec, earlierDate),
+		},
+		"UnorderedRecords": UnorderedRecords{
+			laterDate:   laterRec,
+			earlierDate: earlierRec,
+		},
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates: []record.Date{laterDate, earlierDate},
+			UnorderedRecords: UnorderedRecords{
+				laterDate:   laterRec,
+				earlierDate: earlierRec,
+			},
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			result, err := records.ConvertDecimal(earlierDate, decimal.MustParse("1000.00"), "JPY", "USD")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "150000.0000000000000000", result.String())
+			}
+
+			_, err = records.ConvertDecimal(record.NewDate(2024, 2, 27), decimal.NewFromInt(1), "JPY", "USD")
+			assert.ErrorIs(t, err, ErrRatesRecordNotFound)
+
+			_, err = records.ConvertDecimal(earlierDate, decimal.NewFromInt(1), "XXX", "USD")
+			assert.ErrorIs(t, err, record.ErrRateNotFound)
+
+			result, err = records.ConvertDecimalApproximate(record.NewDate(2024, 2, 27), decimal.MustParse("1000.00"), "JPY", "EUR", DefaultRangeLim)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "163000.0000000000000000", result.String())
+			}
+
+			_, err = records.ConvertDecimalApproximate(record.NewDate(2023, 2, 27), decimal.NewFromInt(1), "JPY", "USD", 1)
+			assert.ErrorIs(t, err, ErrRateApproximationFailed)
+
+			minors, err := records.ConvertMinorsDecimal(laterDate, 1, "USD", "JPY")
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(0), minors)
+			}
+
+			minors, err = records.ConvertMinorsDecimal(laterDate, 100_000, "JPY", "USD")
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(15_045_872), minors)
+			}
+
+			_, err = records.ConvertMinorsDecimal(record.NewDate(2024, 2, 27), 1, "JPY", "USD")
+			assert.ErrorIs(t, err, ErrRatesRecordNotFound)
+
+			minors, err = records.ConvertMinorsDecimalApproximate(record.NewDate(2024, 2, 27), 100, "JPY", "EUR", DefaultRangeLim)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(16_300), minors)
+			}
+
+			_, err = records.ConvertMinorsDecimalApproximate(record.NewDate(2023, 2, 27), 1, "JPY", "USD", 1)
+			assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		})
+	}
+}
+
+func TestRecords_ConvertMinors_Rounding(t *testing.T) {
+	var (
+		earlierDate = record.NewDate(2024, 2, 26)
+		laterDate   = record.NewDate(2024, 2, 28)
+		rec         = record.Record{"USD": 1, "XXX": 2}
+	)
+
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(rec, laterDate),
+			record.NewWithDate(rec, earlierDate),
+		},
+		"UnorderedRecords": UnorderedRecords{
+			laterDate:   rec,
+			earlierDate: rec,
+		},
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates:            []record.Date{laterDate, earlierDate},
+			UnorderedRecords: UnorderedRecords{laterDate: rec, earlierDate: rec},
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			var (
+				missingDate = record.NewDate(2024, 2, 27)
+				opt         = record.WithRounding(rounding.HalfEven)
+			)
+
+			result, err := records.ConvertMinors(laterDate, -5, "USD", "XXX", opt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(-2), result)
+			}
+
+			result, err = records.ConvertMinorsApproximate(missingDate, 5, "USD", "XXX", DefaultRangeLim, opt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(2), result)
+			}
+
+			result, err = records.ConvertMinorsDecimal(laterDate, -7, "USD", "XXX", opt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(-4), result)
+			}
+
+			result, err = records.ConvertMinorsDecimalApproximate(missingDate, 7, "USD", "XXX", DefaultRangeLim, opt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(4), result)
+			}
+
+			decimalResult, err := records.ConvertDecimal(laterDate, decimal.MustParse("1e-16"), "USD", "XXX", opt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "0.0000000000000000", decimalResult.String())
+			}
+
+			decimalResult, err = records.ConvertDecimalApproximate(missingDate, decimal.MustParse("1e-16"), "USD", "XXX", DefaultRangeLim)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "0.0000000000000001", decimalResult.String())
+			}
+		})
+	}
+}
+
+func TestRecords_Currency(t *testing.T) {
+	var (
+		earlierDate = record.NewDate(2024, 2, 26)
+		laterDate   = record.NewDate(2024, 2, 28)
+		missingDate = record.NewDate(2024, 2, 27)
+		earlierRec  = record.Record{"EUR": 1, "USD": 2, "JPY": 2}
+		laterRec    = record.Record{"EUR": 1, "USD": 4, "JPY": 2}
+	)
+
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(laterRec, laterDate),
+			record.NewWithDate(earlierRec, earlierDate),
+		},
+		"UnorderedRecords": UnorderedRecords{
+			laterDate:   laterRec,
+			earlierDate: earlierRec,
+		},
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates:            []record.Date{laterDate, earlierDate},
+			UnorderedRecords: UnorderedRecords{laterDate: laterRec, earlierDate: earlierRec},
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			t.Run("rate", func(t *testing.T) {
+				rate, err := Rate(records, laterDate, currency.USD)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(4), rate)
+				}
+
+				rate, err = ApproximateRate(records, missingDate, "usd", DefaultRangeLim)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(3), rate)
+				}
+
+				approximation, err := ApproximateRateWith(records, missingDate, currency.USD, DefaultRangeLim, Linear)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(3), approximation.Rate)
+					assert.Equal(t, []record.Date{earlierDate, laterDate}, approximation.Sources)
+				}
+			})
+
+			t.Run("convert", func(t *testing.T) {
+				result, err := Convert(records, laterDate, 1, currency.USD, currency.EUR)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(4), result)
+				}
+
+				result, err = ConvertApproximate(records, missingDate, 1, currency.USD, currency.EUR, DefaultRangeLim)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(3), result)
+				}
+
+				conversion, err := ConvertApproximateWith(records, missingDate, 1, currency.USD, currency.EUR, DefaultRangeLim, Linear)
+				if assert.NoError(t, err) {
+					assert.Equal(t, float32(3), conversion.Amount)
+					assert.Equal(t, []record.Date{earlierDate, laterDate}, conversion.Sources)
+				}
+			})
+
+			t.Run("convert minors", func(t *testing.T) {
+				// 1.00 USD is 2 JPY, which has no minor units:
+				result, err := ConvertMinors(records, laterDate, 100, currency.USD, currency.JPY, record.WithCurrencyExponents())
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(2), result)
+				}
+
+				result, err = ConvertMinorsApproximate(records, missingDate, 100, currency.USD, currency.EUR, DefaultRangeLim)
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(300), result)
+				}
+
+				conversion, err := ConvertMinorsApproximateWith(records, missingDate, 100, currency.USD, currency.JPY, DefaultRangeLim, Linear, record.WithCurrencyExponents())
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(2), conversion.Amount)
+				}
+			})
+
+			t.Run("convert decimal", func(t *testing.T) {
+				result, err := ConvertDecimal(records, earlierDate, decimal.NewFromInt(1), currency.EUR, "usd")
+				if assert.NoError(t, err) {
+					assert.True(t, decimal.MustParse("0.5").Equal(result))
+				}
+
+				result, err = ConvertDecimalApproximate(records, missingDate, decimal.NewFromInt(3), currency.EUR, currency.USD, DefaultRangeLim)
+				if assert.NoError(t, err) {
+					assert.True(t, decimal.NewFromInt(1).Equal(result))
+				}
+
+				conversion, err := ConvertDecimalApproximateWith(records, missingDate, decimal.NewFromInt(1), currency.USD, currency.EUR, DefaultRangeLim, Linear)
+				if assert.NoError(t, err) {
+					assert.True(t, decimal.NewFromInt(3).Equal(conversion.Amount))
+				}
+			})
+
+			t.Run("convert minors decimal", func(t *testing.T) {
+				result, err := ConvertMinorsDecimal(records, laterDate, 100, currency.USD, currency.JPY, record.WithCurrencyExponents())
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(2), result)
+				}
+
+				result, err = ConvertMinorsDecimalApproximate(records, missingDate, 100, currency.USD, currency.EUR, DefaultRangeLim)
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(300), result)
+				}
+
+				conversion, err := ConvertMinorsDecimalApproximateWith(records, missingDate, 100, currency.USD, currency.JPY, DefaultRangeLim, Linear, record.WithCurrencyExponents())
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(2), conversion.Amount)
+				}
+			})
+
+			t.Run("missing rate", func(t *testing.T) {
+				_, err := Rate(records, laterDate, currency.GBP)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+
+				_, err = ApproximateRate(records, missingDate, currency.GBP, DefaultRangeLim)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+
+				_, err = Convert(records, laterDate, 1, currency.USD, currency.GBP)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+
+				_, err = ConvertMinorsDecimal(records, laterDate, 1, currency.GBP, currency.USD)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+			})
+
+			t.Run("unknown currency", func(t *testing.T) {
+				_, err := Rate(records, laterDate, "EURO")
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ApproximateRateWith(records, missingDate, "", DefaultRangeLim, Linear)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = Convert(records, laterDate, 1, "EURO", currency.USD)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ConvertApproximate(records, missingDate, 1, currency.USD, "US", DefaultRangeLim)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ConvertMinors(records, laterDate, 1, "XXX", currency.USD)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ConvertMinorsApproximateWith(records, missingDate, 1, currency.USD, "XXX", DefaultRangeLim, Linear)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ConvertDecimalApproximateWith(records, missingDate, decimal.NewFromInt(1), currency.USD, "US", DefaultRangeLim, Linear)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+
+				_, err = ConvertMinorsDecimalApproximate(records, missingDate, 1, "EURO", currency.USD, DefaultRangeLim)
+				assert.ErrorIs(t, err, currency.ErrUnknownCurrency)
+			})
+		})
+	}
+}
+
+func TestRecords_Rebase(t *testing.T) {
+	var (
+		date1 = record.NewDate(2024, 2, 28)
+		date2 = record.NewDate(2024, 2, 27)
+		date3 = record.NewDate(2024, 2, 26)
+		date4 = record.NewDate(2024, 2, 23)
+		rec1  = record.Record{"EUR": 1, "USD": 2, "JPY": 8}
+		rec2  = record.Record{"EUR": 1, "JPY": 10}
+		rec3  = record.Record{"EUR": 1, "USD": 4, "JPY": 16}
+		rec4  = record.Record{"EUR": 1, "USD": 0}
+
+		expectedRec1 = record.Record{"EUR": 0.5, "USD": 1, "JPY": 4}
+		expectedRec3 = record.Record{"EUR": 0.25, "USD": 1, "JPY": 4}
+		expectedDrop = []record.Date{date2, date4}
+	)
+
+	t.Run("OrderedRecords", func(t *testing.T) {
+		records := OrderedRecords{
+			record.NewWithDate(rec1, date1),
+			record.NewWithDate(rec2, date2),
+			record.NewWithDate(rec3, date3),
+			record.NewWithDate(rec4, date4),
+		}
+
+		rebased, dropped := records.Rebase("USD")
+		assert.Equal(t, OrderedRecords{
+			record.NewWithDate(expectedRec1, date1),
+			record.NewWithDate(expectedRec3, date3),
+		}, rebased)
+		assert.Equal(t, expectedDrop, dropped)
+
+		// the original records are not modified:
+		assert.Equal(t, rec1, records[0].Record)
+	})
+
+	t.Run("UnorderedRecords", func(t *testing.T) {
+		records := UnorderedRecords{date1: rec1, date2: rec2, date3: rec3, date4: rec4}
+
+		rebased, dropped := records.Rebase("USD")
+		assert.Equal(t, UnorderedRecords{date1: expectedRec1, date3: expectedRec3}, rebased)
+		assert.Equal(t, expectedDrop, dropped)
+	})
+
+	t.Run("OrderedUnorderedRecords", func(t *testing.T) {
+		records := &OrderedUnorderedRecords{
+			Dates:            []record.Date{date1, date2, date3, date4},
+			UnorderedRecords: UnorderedRecords{date1: rec1, date2: rec2, date3: rec3, date4: rec4},
+		}
+
+		rebased, dropped := records.Rebase("USD")
+		assert.Equal(t, &OrderedUnorderedRecords{
+			Dates:            []record.Date{date1, date3},
+			UnorderedRecords: UnorderedRecords{date1: expectedRec1, date3: expectedRec3},
+		}, rebased)
+		assert.Equal(t, expectedDrop, dropped)
+
+		rate, found := rebased.Rate(date1, "EUR")
+		if assert.True(t, found) {
+			assert.Equal(t, float32(0.5), rate) // EUR per 1 USD
+		}
+	})
+
+	t.Run("nothing dropped", func(t *testing.T) {
+		records := OrderedRecords{record.NewWithDate(rec1, date1)}
+
+		rebased, dropped := records.Rebase("JPY")
+		assert.Equal(t, OrderedRecords{
+			record.NewWithDate(record.Record{"EUR": 0.125, "USD": 0.25, "JPY": 1}, date1),
+		}, rebased)
+		assert.Empty(t, dropped)
+	})
+}
+
+func TestRecords_Range(t *testing.T) {
+	var (
+		date1 = record.NewDate(2024, 2, 28)
+		date2 = record.NewDate(2024, 2, 27)
+		date3 = record.NewDate(2024, 2, 23)
+		date4 = record.NewDate(2024, 2, 22)
+		rec1  = record.Record{"USD": 1.1}
+		rec2  = record.Record{"USD": 1.2}
+		rec3  = record.Record{"USD": 1.3}
+		rec4  = record.Record{"USD": 1.4}
+	)
+
+	implementations := map[string]func() Records{
+		"OrderedRecords": func() Records {
+			return OrderedRecords{
+				record.NewWithDate(rec1, date1),
+				record.NewWithDate(rec2, date2),
+				record.NewWithDate(rec3, date3),
+				record.NewWithDate(rec4, date4),
+			}
+		},
+		"UnorderedRecords": func() Records {
+			return UnorderedRecords{date1: rec1, date2: rec2, date3: rec3, date4: rec4}
+		},
+		"OrderedUnorderedRecords": func() Records {
+			return &OrderedUnorderedRecords{
+				Dates:            []record.Date{date1, date2, date3, date4},
+				UnorderedRecords: UnorderedRecords{date1: rec1, date2: rec2, date3: rec3, date4: rec4},
+			}
+		},
+	}
+
+	for name, newRecords := range implementations {
+		t.Run(name, func(t *testing.T) {
+			records := newRecords()
+
+			assert.Equal(t, 4, records.Len())
+
+			first, found := records.First()
+			if assert.True(t, found) {
+				assert.Equal(t, record.NewWithDate(rec4, date4), first)
+			}
+
+			last, found := records.Last()
+			if assert.True(t, found) {
+				assert.Equal(t, record.NewWithDate(rec1, date1), last)
+			}
+
+			t.Run("inclusive bounds", func(t *testing.T) {
+				subRecords := records.Range(date3, date2)
+				assert.IsType(t, records, subRecords)
+				assert.Equal(t, 2, subRecords.Len())
+				assert.Equal(t, []record.WithDate{
+					record.NewWithDate(rec2, date2),
+					record.NewWithDate(rec3, date3),
+				}, subRecords.Slice())
+			})
+
+			t.Run("bounds without records", func(t *testing.T) {
+				subRecords := records.Range(record.NewDate(2024, 2, 24), record.NewDate(2024, 3, 31))
+				assert.Equal(t, []record.WithDate{
+					record.NewWithDate(rec1, date1),
+					record.NewWithDate(rec2, date2),
+				}, subRecords.Slice())
+
+				first, found := subRecords.First()
+				if assert.True(t, found) {
+					assert.Equal(t, date2, first.Date)
+				}
+			})
+
+			t.Run("whole range", func(t *testing.T) {
+				subRecords := records.Range(record.MinDate, date1)
+				assert.Equal(t, records.Slice(), subRecords.Slice())
+			})
+
+			t.Run("empty range", func(t *testing.T) {
+				for _, subRecords := range []Records{
+					records.Range(record.NewDate(2024, 2, 24), record.NewDate(2024, 2, 26)),
+					records.Range(date1, date4), // from is after to
+					records.Range(record.NewDate(2025, 1, 1), record.NewDate(2025, 12, 31)),
+				} {
+					assert.IsType(t, records, subRecords)
+					assert.Equal(t, 0, subRecords.Len())
+					assert.Empty(t, subRecords.Slice())
+
+					_, found := subRecords.First()
+					assert.False(t, found)
+
+					_, found = subRecords.Last()
+					assert.False(t, found)
+				}
+			})
+
+			t.Run("time.Time bounds", func(t *testing.T) {
+				subRecords := records.Range(time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 28, 23, 0, 0, 0, time.UTC))
+				assert.Equal(t, []record.WithDate{record.NewWithDate(rec1, date1)}, subRecords.Slice())
+			})
+		})
+	}
+}
+
+func TestRecords_ApproximateWith(t *testing.T) {
+	var (
+		earlierDate = record.NewDate(2024, 2, 23)
+		laterDate   = record.NewDate(2024, 2, 27)
+		date        = record.NewDate(2024, 2, 24)
+		earlierRec  = record.Record{"EUR": 1, "USD": 1, "RUB": 100}
+		laterRec    = record.Record{"EUR": 1, "USD": 5, "JPY": 160}
+	)
+
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(laterRec, laterDate),
+			record.NewWithDate(earlierRec, earlierDate),
+		},
+		"UnorderedRecords": UnorderedRecords{
+			laterDate:   laterRec,
+			earlierDate: earlierRec,
+		},
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates:            []record.Date{laterDate, earlierDate},
+			UnorderedRecords: UnorderedRecords{laterDate: laterRec, earlierDate: earlierRec},
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			approximation, ok := records.ApproximateRateWith(date, "USD", DefaultRangeLim, Linear)
+			if assert.True(t, ok) {
+				assert.Equal(t, Approximation{
+					Rate:       2,
+					Sources:    []record.Date{earlierDate, laterDate},
+					Provenance: Provenance{Method: "linear", Earlier: earlierDate, Later: laterDate, Distance: 3},
+				}, approximation)
+			}
+
+			approximation, ok = records.ApproximateRateWith(date, "JPY", DefaultRangeLim, PreviousAvailable)
+			assert.False(t, ok)
+
+			approximation, ok = records.ApproximateRateWith(date, "USD", 2, Linear)
+			if assert.True(t, ok) {
+				assert.Equal(t, Approximation{
+					Rate:       1,
+					Sources:    []record.Date{earlierDate},
+					Provenance: Provenance{Method: "linear", Earlier: earlierDate, Distance: 1},
+				}, approximation)
+			}
+
+			// RUB is discontinued and JPY is not published yet on the date:
+			ratesApproximation, ok := records.ApproximateRatesWith(date, DefaultRangeLim, Linear)
+			if assert.True(t, ok) {
+				assert.Equal(t, RatesApproximation{
+					Rates:      record.Record{"EUR": 1, "USD": 2},
+					Sources:    []record.Date{earlierDate, laterDate},
+					Provenance: Provenance{Method: "linear", Earlier: earlierDate, Later: laterDate, Distance: 3},
+				}, ratesApproximation)
+			}
+
+			ratesApproximation, ok = records.ApproximateRatesWith(date, DefaultRangeLim, PreviousAvailable)
+			if assert.True(t, ok) {
+				assert.Equal(t, RatesApproximation{
+					Rates:      record.Record{"EUR": 1, "USD": 1},
+					Sources:    []record.Date{earlierDate},
+					Provenance: Provenance{Method: "previous-available", Earlier: earlierDate, Distance: 1},
+				}, ratesApproximation)
+			}
+
+			_, ok = records.ApproximateRatesWith(record.NewDate(2024, 2, 28), DefaultRangeLim, NextAvailable)
+			assert.False(t, ok)
+
+			// the default approximator is Midpoint:
+			rates, ok := records.ApproximateRates(date, DefaultRangeLim)
+			ratesApproximation, _ = records.ApproximateRatesWith(date, DefaultRangeLim, Midpoint)
+			if assert.True(t, ok) {
+				assert.Equal(t, ratesApproximation.Rates, rates)
+				assert.Equal(t, float32(3), rates["USD"])
+			}
+		})
+	}
+}
+
+func TestRecords_ConvertApproximateWith(t *testing.T) {
+	var (
+		earlierDate = record.NewDate(2024, 2, 23)
+		laterDate   = record.NewDate(2024, 2, 27)
+		date        = record.NewDate(2024, 2, 24)
+		earlierRec  = record.Record{"EUR": 1, "USD": 1}
+		laterRec    = record.Record{"EUR": 1, "USD": 5}
+
+		expectedProvenance = Provenance{Method: "linear", Earlier: earlierDate, Later: laterDate, Distance: 3}
+		expectedSources    = []record.Date{earlierDate, laterDate}
+	)
+
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(laterRec, laterDate),
+			record.NewWithDate(earlierRec, earlierDate),
+		},
+		"UnorderedRecords": UnorderedRecords{
+			laterDate:   laterRec,
+			earlierDate: earlierRec,
+		},
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates:            []record.Date{laterDate, earlierDate},
+			UnorderedRecords: UnorderedRecords{laterDate: laterRec, earlierDate: earlierRec},
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			// the linearly approximated USD rate is 2:
+			result, err := records.ConvertApproximateWith(date, 1, "USD", "EUR", DefaultRangeLim, Linear)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ApproximatedConversion[float32]{
+					Amount:     2,
+					Sources:    expectedSources,
+					Provenance: expectedProvenance,
+				}, result)
+				assert.True(t, result.HasEarlier())
+				assert.True(t, result.HasLater())
+			}
+
+			minors, err := records.ConvertMinorsApproximateWith(date, 100, "USD", "EUR", DefaultRangeLim, Linear)
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(200), minors.Amount)
+				assert.Equal(t, expectedProvenance, minors.Provenance)
+			}
+
+			decimalResult, err := records.ConvertDecimalApproximateWith(date, decimal.NewFromInt(3), "EUR", "USD", DefaultRangeLim, Linear)
+			if assert.NoError(t, err) {
+				assert.True(t, decimal.MustParse("1.5").Equal(decimalResult.Amount))
+				assert.Equal(t, expectedSources, decimalResult.Sources)
+				assert.Equal(t, expectedProvenance, decimalResult.Provenance)
+			}
+
+			minors, err = records.ConvertMinorsDecimalApproximateWith(date, 300, "EUR", "USD", DefaultRangeLim, PreviousAvailable, record.WithRounding(rounding.Floor))
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(300), minors.Amount)
+				assert.Equal(t, Provenance{Method: "previous-available", Earlier: earlierDate, Distance: 1}, minors.Provenance)
+				assert.False(t, minors.HasLater())
+			}
+
+			_, err = records.ConvertApproximateWith(date, 1, "USD", "JPY", DefaultRangeLim, Linear)
+			assert.ErrorIs(t, err, ErrRateApproximationFailed)
+
+			_, err = records.ConvertApproximateWith(record.NewDate(2024, 2, 28), 1, "USD", "EUR", DefaultRangeLim, NextAvailable)
+			assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		})
+	}
+}
+
+func TestRecords_ApproximateMissingCurrency(t *testing.T) {
+	var (
+		// OLD is discontinued after 2024-01-05, NEW is published since 2024-03-08
+		// and GAP is absent between 2024-01-05 and 2024-03-01 and on 2024-03-05:
+		date1 = record.NewDate(2024, 3, 8)
+		date2 = record.NewDate(2024, 3, 5)
+		date3 = record.NewDate(2024, 3, 1)
+		date4 = record.NewDate(2024, 2, 1)
+		date5 = record.NewDate(2024, 1, 5)
+		date6 = record.NewDate(2024, 1, 4)
+		rec1  = record.Record{"EUR": 1, "USD": 5, "NEW": 10, "GAP": 2}
+		rec2  = record.Record{"EUR": 1, "USD": 4}
+		rec3  = record.Record{"EUR": 1, "USD": 3, "GAP": 2}
+		rec4  = record.Record{"EUR": 1, "USD": 2}
+		rec5  = record.Record{"EUR": 1, "USD": 1, "GAP": 1, "OLD": 5}
+		rec6  = record.Record{"EUR": 1, "USD": 1, "OLD": 5}
+	)
+
+	unorderedRecords := UnorderedRecords{date1: rec1, date2: rec2, date3: rec3, date4: rec4, date5: rec5, date6: rec6}
+	implementations := map[string]Records{
+		"OrderedRecords": OrderedRecords{
+			record.NewWithDate(rec1, date1),
+			record.NewWithDate(rec2, date2),
+			record.NewWithDate(rec3, date3),
+			record.NewWithDate(rec4, date4),
+			record.NewWithDate(rec5, date5),
+			record.NewWithDate(rec6, date6),
+		},
+		"UnorderedRecords": unorderedRecords,
+		"OrderedUnorderedRecords": &OrderedUnorderedRecords{
+			Dates:            []record.Date{date1, date2, date3, date4, date5, date6},
+			UnorderedRecords: unorderedRecords,
+		},
+	}
+
+	for name, records := range implementations {
+		t.Run(name, func(t *testing.T) {
+			t.Run("currency missing from the nearest record", func(t *testing.T) {
+				// the nearest earlier record (2024-03-05) does not contain GAP, so it is taken from 2024-03-01:
+				approximation, ok := records.ApproximateRateWith(record.NewDate(2024, 3, 6), "GAP", 10, Midpoint)
+				if assert.True(t, ok) {
+					assert.Equal(t, float32(2), approximation.Rate)
+					assert.Equal(t, []record.Date{date3, date1}, approximation.Sources)
+					assert.Equal(t, 5, approximation.Distance)
+				}
+
+				rate, ok := records.ApproximateRate(record.NewDate(2024, 3, 6), "GAP", 10)
+				if assert.True(t, ok) {
+					assert.Equal(t, float32(2), rate)
+				}
+
+				rates, ok := records.ApproximateRates(record.NewDate(2024, 3, 6), 10)
+				if assert.True(t, ok) {
+					assert.Equal(t, record.Record{"EUR": 1, "USD": 4.5, "GAP": 2}, rates)
+				}
+
+				// currencies are searched within the range limit only:
+				_, ok = records.ApproximateRateWith(record.NewDate(2024, 3, 6), "GAP", 1, Midpoint)
+				assert.False(t, ok)
+			})
+
+			t.Run("discontinued currency", func(t *testing.T) {
+				_, err := records.ConvertApproximateWith(record.NewDate(2024, 2, 10), 1, "OLD", "EUR", 10, Linear)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
+			})
+
+			t.Run("discontinued currency observed within the range limit", func(t *testing.T) {
+				// OLD is observed on 2024-01-05, but it is not carried forward to 2024-01-08,
+				// because the later record (2024-02-01) within the range limit does not contain it:
+				_, ok := records.ApproximateRate(record.NewDate(2024, 1, 8), "OLD", 30)
+				assert.False(t, ok)
+
+				_, ok = records.ApproximateRateWith(record.NewDate(2024, 1, 8), "OLD", 30, PreviousAvailable)
+				assert.False(t, ok)
+
+				rates, ok := records.ApproximateRates(record.NewDate(2024, 1, 8), 30)
+				if assert.True(t, ok) {
+					assert.NotContains(t, rates, "OLD")
+				}
+
+				_, err := records.ConvertApproximateWith(record.NewDate(2024, 1, 8), 1, "OLD", "EUR", 30, PreviousAvailable)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
+
+				_, err = records.ConvertApproximate(record.NewDate(2024, 1, 8), 1, "OLD", "EUR", 30)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
+			})
+
+			t.Run("not yet published currency observed within the range limit", func(t *testing.T) {
+				_, err := records.ConvertMinorsApproximate(record.NewDate(2024, 3, 6), 100, "EUR", "NEW", 10)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
+
+				_, err = records.ConvertDecimalApproximateWith(record.NewDate(2024, 3, 6), decimal.NewFromInt(1), "NEW", "EUR", 10, NextAvailable)
+				assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
+			})
+
+			t.Run("legacy conversions explain missing rates", func(t *testing.T) {
+				_, err := records.ConvertDecimalApproximate(record.NewDate(2024, 2, 10), decimal.NewFromInt(1), "GAP", "USD", 10)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyTemporarilyAbsent)
+
+				_, err = records.ConvertMinorsDecimalApproximate(record.NewDate(2024, 2, 10), 100, "XXX", "USD", 10)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+			})
+
+			t.Run("not yet published currency", func(t *testing.T) {
+				_, err := records.ConvertMinorsApproximateWith(record.NewDate(2024, 2, 10), 100, "EUR", "NEW", 10, Linear)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
+			})
+
+			t.Run("temporarily absent currency", func(t *testing.T) {
+				_, err := records.ConvertDecimalApproximateWith(record.NewDate(2024, 2, 10), decimal.NewFromInt(1), "GAP", "USD", 10, Linear)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, ErrCurrencyTemporarilyAbsent)
+
+				// GAP is observed on 2024-01-05, but not in the later record (2024-02-01) within the range limit:
+				_, ok := records.ApproximateRate(record.NewDate(2024, 1, 20), "GAP", 15)
+				assert.False(t, ok)
+
+				_, err = records.ConvertApproximate(record.NewDate(2024, 1, 20), 1, "GAP", "USD", 15)
+				assert.ErrorIs(t, err, ErrCurrencyTemporarilyAbsent)
+			})
+
+			t.Run("after the time series", func(t *testing.T) {
+				// there are no later records, so the latest rates are used as is:
+				rate, ok := records.ApproximateRate(record.NewDate(2024, 3, 10), "NEW", 10)
+				if assert.True(t, ok) {
+					assert.Equal(t, float32(10), rate)
+				}
+			})
+
+			t.Run("unknown currency", func(t *testing.T) {
+				_, err := records.ConvertMinorsDecimalApproximateWith(record.NewDate(2024, 2, 10), 100, "XXX", "USD", 10, Linear)
+				assert.ErrorIs(t, err, ErrRateApproximationFailed)
+				assert.ErrorIs(t, err, record.ErrRateNotFound)
+			})
+
+			t.Run("unknown reason", func(t *testing.T) {
+				for _, err := range []error{
+					// the date is out of the time series:
+					func() error {
+						_, err := records.ConvertApproximateWith(record.NewDate(2025, 1, 1), 1, "USD", "EUR", 10, Linear)
+						return err
+					}(),
+					// the rate is observed, but the approximator does not use earlier rates:
+					func() error {
+						_, err := records.ConvertApproximateWith(record.NewDate(2024, 2, 10), 1, "USD", "EUR", 10, NextAvailable)
+						return err
+					}(),
+				} {
+					assert.ErrorIs(t, err, ErrRateApproximationFailed)
+					assert.NotErrorIs(t, err, ErrCurrencyDiscontinued)
+					assert.NotErrorIs(t, err, ErrCurrencyNotYetPublished)
+					assert.NotErrorIs(t, err, ErrCurrencyTemporarilyAbsent)
+					assert.NotErrorIs(t, err, record.ErrRateNotFound)
+				}
+			})
+		})
+	}
+}
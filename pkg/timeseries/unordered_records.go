@@ -2,8 +2,10 @@ package timeseries
 
 import (
 	"github.com/jieggii/ecbratex/pkg/date"
+	"github.com/jieggii/ecbratex/pkg/decimal"
 	"github.com/jieggii/ecbratex/pkg/record"
 	"github.com/jieggii/ecbratex/pkg/xml"
+	"io"
 	"sort"
 )
 
@@ -13,18 +15,32 @@ type UnorderedRecords map[record.Date]record.Record
 
 // NewUnorderedRecordsFromXML creates a new UnorderedRecords from [xml.Data].
 func NewUnorderedRecordsFromXML(xmlData *xml.Data) (UnorderedRecords, error) {
-	records := make(UnorderedRecords)
+	records := make(UnorderedRecords, len(xmlData.Cubes))
 	for _, cube := range xmlData.Cubes {
-		recDate, err := record.DateFromString(cube.Date)
+		recDate, rec, err := newRecordFromCube(cube)
 		if err != nil {
 			return nil, err
 		}
+		records[recDate] = rec
+	}
 
-		records[recDate] = record.New()
-		for _, rate := range cube.Rates {
-			records[recDate][rate.Currency] = rate.Rate
+	return records, nil
+}
+
+// NewUnorderedRecordsFromReader creates a new UnorderedRecords from XML document read from r.
+// Unlike NewUnorderedRecordsFromXML, it does not keep the whole decoded document in memory, see [xml.DecodeCubes].
+func NewUnorderedRecordsFromReader(r io.Reader) (UnorderedRecords, error) {
+	records := make(UnorderedRecords)
+	err := xml.DecodeCubes(r, func(cube xml.DataCube) error {
+		recDate, rec, err := newRecordFromCube(cube)
+		if err != nil {
+			return err
 		}
-		records[recDate]["EUR"] = 1 // add EUR rate for convenience
+		records[recDate] = rec
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return records, nil
@@ -61,6 +77,54 @@ func (r UnorderedRecords) Map() map[record.Date]record.Record {
 	return r
 }
 
+// Len returns number of records.
+// Operates on O(1) time complexity.
+func (r UnorderedRecords) Len() int {
+	return len(r)
+}
+
+// First returns the earliest record and a boolean indicating whether there are any records.
+// Operates on O(n) time complexity.
+func (r UnorderedRecords) First() (record.WithDate, bool) {
+	var first record.WithDate
+	found := false
+	for d, rec := range r {
+		if !found || d.Before(first.Date) {
+			first = record.NewWithDate(rec, d)
+			found = true
+		}
+	}
+	return first, found
+}
+
+// Last returns the latest record and a boolean indicating whether there are any records.
+// Operates on O(n) time complexity.
+func (r UnorderedRecords) Last() (record.WithDate, bool) {
+	var last record.WithDate
+	found := false
+	for d, rec := range r {
+		if !found || d.After(last.Date) {
+			last = record.NewWithDate(rec, d)
+			found = true
+		}
+	}
+	return last, found
+}
+
+// Range returns UnorderedRecords containing records dated within the [from, to] interval.
+// Operates on O(n) time complexity.
+func (r UnorderedRecords) Range(from date.Date, to date.Date) Records {
+	fromDate, toDate := record.DateFromDate(from), record.DateFromDate(to)
+
+	result := make(UnorderedRecords)
+	for d, rec := range r {
+		if !d.Before(fromDate) && !d.After(toDate) {
+			result[d] = rec
+		}
+	}
+	return result
+}
+
 // Rates returns rates on the given date.
 // Operates on O(1) time complexity.
 func (r UnorderedRecords) Rates(date date.Date) (record.Record, bool) {
@@ -87,88 +151,35 @@ func (r UnorderedRecords) Rate(date date.Date, currency string) (float32, bool)
 	return rate, true
 }
 
-// ApproximateRates approximates and returns approximated rates on the given date.
+// ApproximateRates approximates and returns approximated rates on the given date using [Midpoint] approximator.
 // Operates on O(rangeLim) time complexity.
 func (r UnorderedRecords) ApproximateRates(date date.Date, rangeLim int) (record.Record, bool) {
-	recDate := record.DateFromDate(date)
-
-	// find the closest earlier rate records:
-	earlierRec, earlierRecFound := r.nearestEarlierRecord(recDate, rangeLim)
-	laterRec, laterRecFound := r.nearestLaterRecord(recDate, rangeLim)
-
-	// if neither earlier nor later rate records were found:
-	if !earlierRecFound && !laterRecFound {
-		return nil, false
-	}
-
-	// return later record if earlier record was not found:
-	if !earlierRecFound {
-		return laterRec, true
-	}
-
-	// return earlier record if later record was not found:
-	if !laterRecFound {
-		return earlierRec, true
-	}
-
-	// approximate the closest earlier and later records if both were found
-	// (calculating average where possible or using later or earlier rates):
-	ratesRecord := record.New()
-	for currency, earlierRate := range earlierRec {
-		laterRate, found := laterRec.Rate(currency)
-		if !found {
-			ratesRecord[currency] = earlierRate
-			continue
-		}
-		ratesRecord[currency] = (earlierRate + laterRate) / 2
-	}
-
-	// fill ratesRecord with possible missing rates from later records:
-	for currency, laterRate := range laterRec {
-		_, found := ratesRecord[currency]
-		if !found {
-			ratesRecord[currency] = laterRate
-		}
-	}
-
-	return ratesRecord, true
+	approximation, ok := r.ApproximateRatesWith(date, rangeLim, Midpoint)
+	return approximation.Rates, ok
 }
 
-// ApproximateRate approximates and returns approximated rate of the given currency on the given date.
+// ApproximateRate approximates and returns approximated rate of the given currency on the given date
+// using [Midpoint] approximator.
 // Operates on O(rangeLim) time complexity.
 func (r UnorderedRecords) ApproximateRate(date date.Date, currency string, rangeLim int) (float32, bool) {
-	recDate := record.DateFromDate(date)
-	earlierRec, earlierRecFound := r.nearestEarlierRecord(recDate, rangeLim)
-	laterRec, laterRecFound := r.nearestLaterRecord(recDate, rangeLim)
-
-	if !earlierRecFound && !laterRecFound {
-		return 0, false
-	}
-
-	if !earlierRecFound {
-		return laterRec.Rate(currency)
-	}
-
-	if !laterRecFound {
-		return earlierRec.Rate(currency)
-	}
-
-	earlierRate, foundEarlierRate := earlierRec.Rate(currency)
-	laterRate, foundLaterRate := laterRec.Rate(currency)
-
-	if !foundEarlierRate && !foundLaterRate {
-		return 0, false
-	}
-
-	if !foundEarlierRate {
-		return laterRate, true
-	}
+	approximation, ok := r.ApproximateRateWith(date, currency, rangeLim, Midpoint)
+	return approximation.Rate, ok
+}
 
-	if !foundLaterRate {
-		return earlierRate, true
-	}
+// ApproximateRatesWith approximates rates on the given date using the given approximator.
+// Operates on O(rangeLim) time complexity.
+func (r UnorderedRecords) ApproximateRatesWith(date date.Date, rangeLim int, approximator Approximator) (RatesApproximation, bool) {
+	recDate := record.DateFromDate(date)
+	earlier, later := r.window(recDate, rangeLim)
+	return approximateRates(recDate, earlier, later, approximator)
+}
 
-	return (earlierRate + laterRate) / 2, true
+// ApproximateRateWith approximates rate of the given currency on the given date using the given approximator.
+// Operates on O(rangeLim) time complexity.
+func (r UnorderedRecords) ApproximateRateWith(date date.Date, currency string, rangeLim int, approximator Approximator) (Approximation, bool) {
+	recDate := record.DateFromDate(date)
+	earlier, later := r.window(recDate, rangeLim)
+	return approximateRate(recDate, currency, earlier, later, approximator)
 }
 
 // Convert converts amount from one currency to another on the given date.
@@ -190,28 +201,19 @@ func (r UnorderedRecords) Convert(date date.Date, amount float32, from string, t
 // using approximated rates within rangeLim days.
 // Operates on O(rangeLim) time complexity.
 func (r UnorderedRecords) ConvertApproximate(date date.Date, amount float32, from string, to string, rangeLim int) (float32, error) {
-	rates, found := r.ApproximateRates(date, rangeLim)
-	if !found {
-		return 0, ErrRateApproximationFailed
-
-	}
-
-	result, err := rates.Convert(amount, from, to)
-	if err != nil {
-		return 0, err
-	}
-	return result, nil
+	conversion, err := convertApproximateWith(r, date, amount, from, to, rangeLim, Midpoint)
+	return conversion.Amount, err
 }
 
 // ConvertMinors converts amount in minor units from one currency to another on the given date.
 // Operates on O(1) time complexity.
-func (r UnorderedRecords) ConvertMinors(date date.Date, amount int64, from string, to string) (int64, error) {
+func (r UnorderedRecords) ConvertMinors(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error) {
 	rec, found := r.Rates(date)
 	if !found {
 		return 0, ErrRatesRecordNotFound
 	}
 
-	result, err := rec.ConvertMinors(amount, from, to)
+	result, err := rec.ConvertMinors(amount, from, to, opts...)
 	if err != nil {
 		return 0, err
 	}
@@ -221,43 +223,130 @@ func (r UnorderedRecords) ConvertMinors(date date.Date, amount int64, from strin
 // ConvertMinorsApproximate converts amount in minor units from one currency to another on the given date,
 // using approximated rates within rangeLim days.
 // Operates on O(rangeLim) time complexity.
-func (r UnorderedRecords) ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int) (int64, error) {
-	rates, found := r.ApproximateRates(date, rangeLim)
-	if !found {
-		return 0, ErrRateApproximationFailed
+func (r UnorderedRecords) ConvertMinorsApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	conversion, err := convertMinorsApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
+}
 
+// ConvertDecimal converts amount from one currency to another on the given date using decimal arithmetic.
+// Operates on O(1) time complexity.
+func (r UnorderedRecords) ConvertDecimal(date date.Date, amount decimal.Decimal, from string, to string, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	rec, found := r.Rates(date)
+	if !found {
+		return decimal.Zero, ErrRatesRecordNotFound
 	}
 
-	result, err := rates.ConvertMinors(amount, from, to)
-	if err != nil {
-		return 0, err
+	return rec.ConvertDecimal(amount, from, to, opts...)
+}
+
+// ConvertDecimalApproximate converts amount from one currency to another on the given date
+// using decimal arithmetic and approximated rates within rangeLim days.
+// Operates on O(rangeLim) time complexity.
+func (r UnorderedRecords) ConvertDecimalApproximate(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, opts ...record.ConvertOption) (decimal.Decimal, error) {
+	conversion, err := convertDecimalApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
+}
+
+// ConvertMinorsDecimal converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic.
+// Operates on O(1) time complexity.
+func (r UnorderedRecords) ConvertMinorsDecimal(date date.Date, amount int64, from string, to string, opts ...record.ConvertOption) (int64, error) {
+	rec, found := r.Rates(date)
+	if !found {
+		return 0, ErrRatesRecordNotFound
 	}
-	return result, nil
+
+	return rec.ConvertMinorsDecimal(amount, from, to, opts...)
 }
 
-// nearestEarlierRecord finds the closest earlier rate record to the given date within the specified range.
+// ConvertMinorsDecimalApproximate converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic and approximated rates within rangeLim days.
 // Operates on O(rangeLim) time complexity.
-func (r UnorderedRecords) nearestEarlierRecord(recDate record.Date, rangeLim int) (record.Record, bool) {
-	for range rangeLim {
-		recDate = recDate.AddDays(-1)
-		rec, found := r.Rates(recDate)
-		if found {
-			return rec, true
+func (r UnorderedRecords) ConvertMinorsDecimalApproximate(date date.Date, amount int64, from string, to string, rangeLim int, opts ...record.ConvertOption) (int64, error) {
+	conversion, err := convertMinorsDecimalApproximateWith(r, date, amount, from, to, rangeLim, Midpoint, opts)
+	return conversion.Amount, err
+}
+
+// ConvertApproximateWith converts amount from one currency to another on the given date
+// using rates approximated by the given approximator and reports their provenance.
+func (r UnorderedRecords) ConvertApproximateWith(date date.Date, amount float32, from string, to string, rangeLim int, approximator Approximator) (ApproximatedConversion[float32], error) {
+	return convertApproximateWith(r, date, amount, from, to, rangeLim, approximator)
+}
+
+// ConvertMinorsApproximateWith converts amount in minor units from one currency to another on the given date
+// using rates approximated by the given approximator and reports their provenance.
+func (r UnorderedRecords) ConvertMinorsApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	return convertMinorsApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// ConvertDecimalApproximateWith converts amount from one currency to another on the given date using decimal
+// arithmetic and rates approximated by the given approximator and reports their provenance.
+func (r UnorderedRecords) ConvertDecimalApproximateWith(date date.Date, amount decimal.Decimal, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[decimal.Decimal], error) {
+	return convertDecimalApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// ConvertMinorsDecimalApproximateWith converts amount in minor units from one currency to another on the given date
+// using decimal arithmetic and rates approximated by the given approximator and reports their provenance.
+func (r UnorderedRecords) ConvertMinorsDecimalApproximateWith(date date.Date, amount int64, from string, to string, rangeLim int, approximator Approximator, opts ...record.ConvertOption) (ApproximatedConversion[int64], error) {
+	return convertMinorsDecimalApproximateWith(r, date, amount, from, to, rangeLim, approximator, opts)
+}
+
+// Rebase returns new UnorderedRecords with all records rebased onto the given base currency, see [record.Record.Rebase].
+// Records missing rate of the base currency are dropped, their dates are returned in anti-chronological order.
+// Operates on O(n) time complexity (plus O(k log k) to sort k dropped dates).
+func (r UnorderedRecords) Rebase(base string) (UnorderedRecords, []record.Date) {
+	rebased := make(UnorderedRecords, len(r))
+	var dropped []record.Date
+	for recDate, rec := range r {
+		rebasedRec, err := rec.Rebase(base)
+		if err != nil {
+			dropped = append(dropped, recDate)
+			continue
 		}
+		rebased[recDate] = rebasedRec
 	}
 
-	return nil, false
+	sort.Slice(dropped, func(i, j int) bool {
+		return dropped[i].After(dropped[j])
+	})
+	return rebased, dropped
 }
 
-// nearestLaterRecord finds the closest later rate record to the given date within the specified range.
+// window returns records within the specified range before and after the given date, the nearest records first.
 // Operates on O(rangeLim) time complexity.
-func (r UnorderedRecords) nearestLaterRecord(recDate record.Date, rangeLim int) (record.Record, bool) {
-	for range rangeLim {
-		recDate = recDate.AddDays(1)
-		rec, found := r.Rates(recDate)
-		if found {
-			return rec, true
+func (r UnorderedRecords) window(recDate record.Date, rangeLim int) ([]record.WithDate, []record.WithDate) {
+	var earlier, later []record.WithDate
+	for days := 1; days <= rangeLim; days++ {
+		earlierDate := recDate.AddDays(-days)
+		if rec, found := r[earlierDate]; found {
+			earlier = append(earlier, record.NewWithDate(rec, earlierDate))
+		}
+
+		laterDate := recDate.AddDays(days)
+		if rec, found := r[laterDate]; found {
+			later = append(later, record.NewWithDate(rec, laterDate))
+		}
+	}
+	return earlier, later
+}
+
+// currencySpan returns dates of the earliest and the latest records containing rate of the given currency
+// and a boolean indicating whether there are any.
+// Operates on O(n) time complexity.
+func (r UnorderedRecords) currencySpan(currency string) (record.Date, record.Date, bool) {
+	var first, last record.Date
+	found := false
+	for d, rec := range r {
+		if _, ok := rec[currency]; !ok {
+			continue
+		}
+		if !found || d.Before(first) {
+			first = d
+		}
+		if !found || d.After(last) {
+			last = d
 		}
+		found = true
 	}
-	return nil, false
+	return first, last, found
 }
@@ -16,7 +16,7 @@ func TestNewUnorderedRecordsFromXML(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
 				{Date: date.String(), Rates: []xml.DataCubeRate{
-					{"USD", USDRate},
+					{Currency: "USD", Rate: USDRate},
 				}},
 			},
 		}
@@ -36,7 +36,7 @@ func TestNewUnorderedRecordsFromXML(t *testing.T) {
 		data := &xml.Data{
 			Cubes: []xml.DataCube{
 				{Date: "invalid date", Rates: []xml.DataCubeRate{
-					{"USD", 0.9},
+					{Currency: "USD", Rate: 0.9},
 				}},
 			},
 		}
@@ -286,10 +286,12 @@ func TestUnorderedRecords_ApproximateRates(t *testing.T) {
 			date2: rec2,
 		}
 
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
 		result, found := records.ApproximateRates(record.NewDate(2000, 1, 16), rangeLim)
 		if assert.True(t, found) {
 			assert.Equal(t, (rec1["USD"]+rec2["USD"])/2, result["USD"])
-			assert.Equal(t, rec2["RUB"], result["RUB"])
+			assert.NotContains(t, result, "RUB")
 		}
 	})
 
@@ -309,10 +311,12 @@ func TestUnorderedRecords_ApproximateRates(t *testing.T) {
 			date2: rec2,
 		}
 
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
 		result, found := records.ApproximateRates(record.NewDate(2000, 1, 16), rangeLim)
 		if assert.True(t, found) {
 			assert.Equal(t, (rec1["USD"]+rec2["USD"])/2, result["USD"])
-			assert.Equal(t, rec1["RUB"], result["RUB"])
+			assert.NotContains(t, result, "RUB")
 		}
 	})
 
@@ -378,10 +382,10 @@ func TestUnorderedRecords_ApproximateRate(t *testing.T) {
 			date2: rec2,
 		}
 
-		rate, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
-		if assert.True(t, found) {
-			assert.Equal(t, rec2["RUB"], rate)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
+		assert.False(t, found)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing rate in the later record", func(t *testing.T) {
@@ -400,10 +404,10 @@ func TestUnorderedRecords_ApproximateRate(t *testing.T) {
 			date2: rec2,
 		}
 
-		rate, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
-		if assert.True(t, found) {
-			assert.Equal(t, rec1["RUB"], rate)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, found := records.ApproximateRate(record.NewDate(2000, 1, 16), "RUB", rangeLim)
+		assert.False(t, found)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing rate in the both records", func(t *testing.T) {
@@ -571,14 +575,11 @@ func TestUnorderedRecords_ConvertApproximate(t *testing.T) {
 			record.NewDate(2000, 1, 30): record2,
 		}
 
-		result, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (record1["USD"] + record2["USD"]) / 2
-				rubRate = record2["RUB"]
-			)
-			assert.Equal(t, amount*(usdRate/rubRate), result)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the later record", func(t *testing.T) {
@@ -597,14 +598,11 @@ func TestUnorderedRecords_ConvertApproximate(t *testing.T) {
 			record.NewDate(2000, 1, 30): record2,
 		}
 
-		result, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (record1["USD"] + record2["USD"]) / 2
-				rubRate = record1["RUB"]
-			)
-			assert.Equal(t, amount*(usdRate/rubRate), result)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, err := records.ConvertApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the both records", func(t *testing.T) {
@@ -791,15 +789,11 @@ func TestUnorderedRecords_ConvertMinorsApproximate(t *testing.T) {
 			record.NewDate(2000, 1, 30): rec2,
 		}
 
-		result, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (rec1["USD"] + rec2["USD"]) / 2
-				rubRate = rec2["RUB"]
-			)
-			expectedResult := int64(math.Round(amount * float64(usdRate/rubRate)))
-			assert.Equal(t, expectedResult, result)
-		}
+		// RUB is missing from the earlier record, so it is not published yet on the given date
+		// and is not approximated from the later record:
+		_, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyNotYetPublished)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the later record", func(t *testing.T) {
@@ -818,15 +812,11 @@ func TestUnorderedRecords_ConvertMinorsApproximate(t *testing.T) {
 			record.NewDate(2000, 1, 30): rec2,
 		}
 
-		result, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
-		if assert.NoError(t, err) {
-			var (
-				usdRate = (rec1["USD"] + rec2["USD"]) / 2
-				rubRate = rec1["RUB"]
-			)
-			expectedResult := int64(math.Round(amount * float64(usdRate/rubRate)))
-			assert.Equal(t, expectedResult, result)
-		}
+		// RUB is missing from the later record, so it is discontinued on the given date
+		// and is not approximated from the earlier record:
+		_, err := records.ConvertMinorsApproximate(record.NewDate(2000, 1, 16), amount, "USD", "RUB", rangeLim)
+		assert.ErrorIs(t, err, ErrRateApproximationFailed)
+		assert.ErrorIs(t, err, ErrCurrencyDiscontinued)
 	})
 
 	t.Run("rangeLim covering both earlier and later records, missing TO rate in the both records", func(t *testing.T) {
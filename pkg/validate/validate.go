@@ -0,0 +1,260 @@
+// Package validate provides validation of the parsed currency exchange rates data.
+package validate
+
+import (
+	"fmt"
+	"github.com/jieggii/ecbratex/pkg/decimal"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/timeseries"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IssueKind is kind of the found issue.
+type IssueKind uint8
+
+const (
+	// IssueInvalidDate means that date of a record can not be parsed.
+	IssueInvalidDate IssueKind = iota
+
+	// IssueDuplicateDate means that there are multiple records of the same date.
+	IssueDuplicateDate
+
+	// IssueFutureDate means that date of a record is in the future.
+	IssueFutureDate
+
+	// IssueDuplicateCurrency means that there are multiple rates of the same currency within a single record.
+	IssueDuplicateCurrency
+
+	// IssueInvalidCurrencyCode means that currency code does not consist of three uppercase latin letters
+	// as ISO 4217 requires. Codes are not checked against the list of currencies published by the ECB,
+	// so that currencies the ECB starts publishing are accepted.
+	IssueInvalidCurrencyCode
+
+	// IssueInvalidRate means that rate is not a positive number.
+	IssueInvalidRate
+
+	// IssueInexactRate means that the published rate has more significant digits than float32 keeps,
+	// so that decimal methods of [record.Record] do not return it exactly (see [decimal.NewFromFloat32]).
+	IssueInexactRate
+)
+
+// String returns description of the issue kind.
+func (k IssueKind) String() string {
+	switch k {
+	case IssueInvalidDate:
+		return "invalid date"
+	case IssueDuplicateDate:
+		return "duplicate date"
+	case IssueFutureDate:
+		return "future date"
+	case IssueDuplicateCurrency:
+		return "duplicate currency"
+	case IssueInvalidCurrencyCode:
+		return "invalid currency code"
+	case IssueInvalidRate:
+		return "invalid rate"
+	case IssueInexactRate:
+		return "inexact rate"
+	default:
+		return fmt.Sprintf("IssueKind(%d)", k)
+	}
+}
+
+// Issue is a single problem found in the data.
+type Issue struct {
+	// Kind is kind of the issue.
+	Kind IssueKind
+
+	// Date is date of the record the issue was found in.
+	Date string
+
+	// Currency is the currency code the issue relates to.
+	// It is empty if the issue relates to the whole record.
+	Currency string
+}
+
+// String returns human-readable representation of the issue.
+func (i Issue) String() string {
+	if i.Currency == "" {
+		return fmt.Sprintf("%s: %s", i.Date, i.Kind)
+	}
+	return fmt.Sprintf("%s %s: %s", i.Date, i.Currency, i.Kind)
+}
+
+// Report is result of validation.
+type Report struct {
+	// Issues are all found issues in the order of their appearance in the data.
+	Issues []Issue
+}
+
+// Valid reports whether no issues were found.
+func (r Report) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns *Error containing the report if any issues were found and nil otherwise.
+func (r Report) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return &Error{Report: r}
+}
+
+// Error is the error returned if data contains issues.
+type Error struct {
+	Report Report
+}
+
+// Error returns description of all found issues.
+func (e *Error) Error() string {
+	issues := make([]string, len(e.Report.Issues))
+	for i, issue := range e.Report.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid rates data: %d issue(s): %s", len(issues), strings.Join(issues, "; "))
+}
+
+// Option configures validation.
+type Option func(v *validator)
+
+// WithNow sets function returning current time used to detect future dates.
+// time.Now is used by default.
+func WithNow(now func() time.Time) Option {
+	return func(v *validator) {
+		v.now = now
+	}
+}
+
+// validator collects issues of the validated data.
+type validator struct {
+	now    func() time.Time
+	today  record.Date
+	dates  map[record.Date]struct{}
+	report Report
+}
+
+// newValidator creates a new validator configured with the given options.
+func newValidator(opts []Option) *validator {
+	v := &validator{
+		now:   time.Now,
+		dates: make(map[record.Date]struct{}),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	// the ECB publishes rates at 16:00 CET, which is always the same day in UTC:
+	v.today = record.DateFromTime(v.now().UTC())
+	return v
+}
+
+// XMLData validates all cubes of the given data.
+func XMLData(data *xml.Data, opts ...Option) Report {
+	v := newValidator(opts)
+	for _, cube := range data.Cubes {
+		v.checkDate(cube.Date)
+
+		currencies := make(map[string]struct{}, len(cube.Rates))
+		for _, rate := range cube.Rates {
+			if _, found := currencies[rate.Currency]; found {
+				v.add(IssueDuplicateCurrency, cube.Date, rate.Currency)
+			}
+			currencies[rate.Currency] = struct{}{}
+
+			v.checkRate(cube.Date, rate.Currency, rate.Rate)
+			v.checkValue(cube.Date, rate)
+		}
+	}
+	return v.report
+}
+
+// Records validates all records of the given time series.
+// Note that duplicate currencies can not be detected, because rates of each record are stored in a map.
+func Records(records timeseries.Records, opts ...Option) Report {
+	v := newValidator(opts)
+	for _, rec := range records.Slice() {
+		dateString := rec.Date.String()
+		v.checkDate(dateString)
+
+		for _, currency := range sortedCurrencies(rec.Record) {
+			v.checkRate(dateString, currency, rec.Record[currency])
+		}
+	}
+	return v.report
+}
+
+// add adds a new issue to the report.
+func (v *validator) add(kind IssueKind, date string, currency string) {
+	v.report.Issues = append(v.report.Issues, Issue{Kind: kind, Date: date, Currency: currency})
+}
+
+// checkDate checks that date is valid, unique and is not in the future.
+func (v *validator) checkDate(dateString string) {
+	recDate, err := record.DateFromString(dateString)
+	if err != nil {
+		v.add(IssueInvalidDate, dateString, "")
+		return
+	}
+
+	if _, found := v.dates[recDate]; found {
+		v.add(IssueDuplicateDate, dateString, "")
+	}
+	v.dates[recDate] = struct{}{}
+
+	if recDate.After(v.today) {
+		v.add(IssueFutureDate, dateString, "")
+	}
+}
+
+// checkRate checks that currency code is valid and rate is positive.
+func (v *validator) checkRate(date string, currency string, rate float32) {
+	if !isCurrencyCode(currency) {
+		v.add(IssueInvalidCurrencyCode, date, currency)
+	}
+	if !(rate > 0) { // also catches NaN
+		v.add(IssueInvalidRate, date, currency)
+	}
+}
+
+// checkValue checks that the published value of the rate is recovered exactly from its float32 rate.
+// Rates which were not decoded from a document have no published value and are not checked.
+func (v *validator) checkValue(date string, rate xml.DataCubeRate) {
+	if rate.Value == "" || !(rate.Rate > 0) {
+		return
+	}
+
+	value, err := decimal.Parse(rate.Value)
+	if err != nil {
+		v.add(IssueInexactRate, date, rate.Currency)
+		return
+	}
+	if recovered, err := decimal.NewFromFloat32(rate.Rate); err != nil || !recovered.Equal(value) {
+		v.add(IssueInexactRate, date, rate.Currency)
+	}
+}
+
+// isCurrencyCode reports whether code consists of three uppercase latin letters.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedCurrencies returns currencies of the record in alphabetical order to keep the report deterministic.
+func sortedCurrencies(rec record.Record) []string {
+	currencies := make([]string, 0, len(rec))
+	for currency := range rec {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"errors"
+	"github.com/jieggii/ecbratex/pkg/record"
+	"github.com/jieggii/ecbratex/pkg/timeseries"
+	"github.com/jieggii/ecbratex/pkg/xml"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+var now = func() time.Time {
+	return time.Date(2024, 2, 27, 12, 0, 0, 0, time.UTC)
+}
+
+func TestXMLData(t *testing.T) {
+	t.Run("valid data", func(t *testing.T) {
+		data, err := os.ReadFile("./../../testdata/eurofxref-hist.xml")
+		if err != nil {
+			panic(err)
+		}
+		xmlData, err := xml.NewData(data)
+		if err != nil {
+			panic(err)
+		}
+
+		report := XMLData(xmlData, WithNow(now))
+		assert.True(t, report.Valid())
+		assert.NoError(t, report.Err())
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		xmlData := &xml.Data{Cubes: []xml.DataCube{
+			{Date: "2024-02-28", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 1.0856}}},
+			{Date: "2024-02-27", Rates: []xml.DataCubeRate{
+				{Currency: "USD", Rate: 1.0856},
+				{Currency: "USD", Rate: 1.0857},
+				{Currency: "usd", Rate: 1},
+				{Currency: "KZT", Rate: 500}, // not published by the ECB, but valid
+				{Currency: "IDR", Rate: 16976.12, Value: "16976.12"},
+				{Currency: "XAU", Rate: 8589973000, Value: "8589973000"}, // float32 keeps 8589974000
+				{Currency: "JPY", Rate: 0},
+				{Currency: "GBP", Rate: -1},
+			}},
+			{Date: "2024-02-27", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: 1.0856}}},
+			{Date: "invalid date", Rates: []xml.DataCubeRate{{Currency: "USD", Rate: float32(math.NaN())}}},
+		}}
+
+		report := XMLData(xmlData, WithNow(now))
+		assert.False(t, report.Valid())
+		assert.Equal(t, []Issue{
+			{Kind: IssueFutureDate, Date: "2024-02-28"},
+			{Kind: IssueDuplicateCurrency, Date: "2024-02-27", Currency: "USD"},
+			{Kind: IssueInvalidCurrencyCode, Date: "2024-02-27", Currency: "usd"},
+			{Kind: IssueInexactRate, Date: "2024-02-27", Currency: "XAU"},
+			{Kind: IssueInvalidRate, Date: "2024-02-27", Currency: "JPY"},
+			{Kind: IssueInvalidRate, Date: "2024-02-27", Currency: "GBP"},
+			{Kind: IssueDuplicateDate, Date: "2024-02-27"},
+			{Kind: IssueInvalidDate, Date: "invalid date"},
+			{Kind: IssueInvalidRate, Date: "invalid date", Currency: "USD"},
+		}, report.Issues)
+
+		var validationErr *Error
+		if assert.True(t, errors.As(report.Err(), &validationErr)) {
+			assert.Equal(t, report, validationErr.Report)
+			assert.Contains(t, validationErr.Error(), "9 issue(s)")
+			assert.Contains(t, validationErr.Error(), "2024-02-27 USD: duplicate currency")
+		}
+	})
+}
+
+func TestRecords(t *testing.T) {
+	records := timeseries.OrderedRecords{
+		record.NewWithDate(record.Record{"EUR": 1, "USD": 1.0856}, record.NewDate(2024, 3, 1)),
+		record.NewWithDate(record.Record{"EUR": 1, "USD": 1.0856, "JPY": 0}, record.NewDate(2024, 2, 27)),
+		record.NewWithDate(record.Record{"EUR": 1, "USD": 1.0856}, record.NewDate(2024, 2, 27)),
+	}
+
+	report := Records(records, WithNow(now))
+	assert.Equal(t, []Issue{
+		{Kind: IssueFutureDate, Date: "2024-03-01"},
+		{Kind: IssueInvalidRate, Date: "2024-02-27", Currency: "JPY"},
+		{Kind: IssueDuplicateDate, Date: "2024-02-27"},
+	}, report.Issues)
+}
+
+func TestIssueKind_String(t *testing.T) {
+	assert.Equal(t, "duplicate currency", IssueDuplicateCurrency.String())
+	assert.Equal(t, "IssueKind(42)", IssueKind(42).String())
+}
@@ -2,13 +2,56 @@ package xml
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// GesmesNamespace is namespace of the ECB document envelope.
+	GesmesNamespace = "http://www.gesmes.org/xml/2002-08-01"
+
+	// EurofxrefNamespace is namespace of the ECB rate cubes.
+	EurofxrefNamespace = "http://www.ecb.int/vocabulary/2002-08-01/eurofxref"
+
+	// ReferenceRatesSubject is subject of the ECB documents containing reference rates.
+	ReferenceRatesSubject = "Reference rates"
+)
+
+var (
+	// ErrUnexpectedRootElement error indicates that document root element is not the gesmes envelope.
+	ErrUnexpectedRootElement = errors.New("unexpected root element")
+
+	// ErrUnexpectedNamespace error indicates that document does not use the ECB rate cubes namespace.
+	ErrUnexpectedNamespace = errors.New("unexpected namespace")
+
+	// ErrUnexpectedSubject error indicates that document subject is not ReferenceRatesSubject.
+	ErrUnexpectedSubject = errors.New("unexpected subject")
 )
 
 // Data is a struct representing an XML document containing currency exchange rates records.
 type Data struct {
+	// XMLName is name of the document root element, which is Envelope in GesmesNamespace.
+	XMLName xml.Name
+
+	// Namespace is the default namespace of the document, which is EurofxrefNamespace.
+	Namespace string `xml:"xmlns,attr"`
+
+	// Subject is subject of the document, which is ReferenceRatesSubject.
+	Subject string `xml:"subject"`
+
+	// Sender is sender of the document.
+	Sender DataSender `xml:"Sender"`
+
 	Cubes []DataCube `xml:"Cube>Cube"`
 }
 
+// DataSender is a struct representing sender of an XML document.
+type DataSender struct {
+	// Name is name of the sender, for example "European Central Bank".
+	Name string `xml:"name"`
+}
+
 type DataCube struct {
 	Date  string         `xml:"time,attr"`
 	Rates []DataCubeRate `xml:"Cube"`
@@ -17,13 +60,63 @@ type DataCube struct {
 type DataCubeRate struct {
 	Currency string  `xml:"currency,attr"`
 	Rate     float32 `xml:"rate,attr"`
+
+	// Value is the rate exactly as it is written in the document, for example "1.0856".
+	// It is empty if the rate was not decoded from a document.
+	Value string `xml:"-"`
+}
+
+// UnmarshalXML decodes rate element keeping the original value of its rate attribute in Value.
+func (r *DataCubeRate) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type plainDataCubeRate DataCubeRate // prevents recursion
+	if err := decoder.DecodeElement((*plainDataCubeRate)(r), &start); err != nil {
+		return err
+	}
+	r.Value = strings.TrimSpace(attrValue(start, "rate"))
+	return nil
 }
 
 // NewData decodes XML bytes to a new Data.
+// Documents which are not the ECB reference rates envelopes
+// (for example, HTML error pages or unrelated XML files) are rejected.
 func NewData(data []byte) (*Data, error) {
 	xmlData := &Data{}
 	if err := xml.Unmarshal(data, xmlData); err != nil {
 		return nil, err
 	}
+	if err := checkRoot(xmlData.XMLName, xmlData.Namespace); err != nil {
+		return nil, err
+	}
+	if err := checkSubject(xmlData.Subject); err != nil {
+		return nil, err
+	}
 	return xmlData, nil
 }
+
+// Time returns time of the document, which is date of its first (the most recent) cube,
+// or an empty string if the document does not contain any cubes.
+func (d *Data) Time() string {
+	if len(d.Cubes) == 0 {
+		return ""
+	}
+	return d.Cubes[0].Date
+}
+
+// checkRoot checks that root element is the gesmes envelope with EurofxrefNamespace as the default namespace.
+func checkRoot(name xml.Name, namespace string) error {
+	if name.Local != "Envelope" || name.Space != GesmesNamespace {
+		return fmt.Errorf("%w <%s> in namespace %q, expected gesmes:Envelope", ErrUnexpectedRootElement, name.Local, name.Space)
+	}
+	if namespace != EurofxrefNamespace {
+		return fmt.Errorf("%w %q, expected %q", ErrUnexpectedNamespace, namespace, EurofxrefNamespace)
+	}
+	return nil
+}
+
+// checkSubject checks that subject is ReferenceRatesSubject.
+func checkSubject(subject string) error {
+	if subject != ReferenceRatesSubject {
+		return fmt.Errorf("%w %q, expected %q", ErrUnexpectedSubject, subject, ReferenceRatesSubject)
+	}
+	return nil
+}
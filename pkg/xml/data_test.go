@@ -18,6 +18,14 @@ func TestNewData(t *testing.T) {
 		xmlData, err := NewData(data)
 		if assert.NoError(t, err) {
 			assert.NotEmpty(t, xmlData)
+			assert.Equal(t, GesmesNamespace, xmlData.XMLName.Space)
+			assert.Equal(t, EurofxrefNamespace, xmlData.Namespace)
+			assert.Equal(t, ReferenceRatesSubject, xmlData.Subject)
+			assert.Equal(t, "European Central Bank", xmlData.Sender.Name)
+			assert.Equal(t, "2024-02-27", xmlData.Time())
+			if assert.NotEmpty(t, xmlData.Cubes[0].Rates) {
+				assert.Equal(t, DataCubeRate{Currency: "USD", Rate: 1.0856, Value: "1.0856"}, xmlData.Cubes[0].Rates[0])
+			}
 		}
 	})
 
@@ -29,4 +37,34 @@ func TestNewData(t *testing.T) {
 			assert.Empty(t, xmlData)
 		}
 	})
+	t.Run("HTML error page", func(t *testing.T) {
+		var data = []byte(`<html><head><title>Service Unavailable</title></head><body>Try again later</body></html>`)
+
+		xmlData, err := NewData(data)
+		assert.ErrorIs(t, err, ErrUnexpectedRootElement)
+		assert.Nil(t, xmlData)
+	})
+
+	t.Run("unexpected namespace", func(t *testing.T) {
+		var data = []byte(`<gesmes:Envelope xmlns:gesmes="` + GesmesNamespace + `" xmlns="http://example.com">` +
+			`<gesmes:subject>Reference rates</gesmes:subject></gesmes:Envelope>`)
+
+		xmlData, err := NewData(data)
+		assert.ErrorIs(t, err, ErrUnexpectedNamespace)
+		assert.Nil(t, xmlData)
+	})
+
+	t.Run("unexpected subject", func(t *testing.T) {
+		var data = []byte(`<gesmes:Envelope xmlns:gesmes="` + GesmesNamespace + `" xmlns="` + EurofxrefNamespace + `">` +
+			`<gesmes:subject>Something else</gesmes:subject></gesmes:Envelope>`)
+
+		xmlData, err := NewData(data)
+		assert.ErrorIs(t, err, ErrUnexpectedSubject)
+		assert.Nil(t, xmlData)
+	})
+}
+
+func TestData_Time(t *testing.T) {
+	assert.Empty(t, (&Data{}).Time())
+	assert.Equal(t, "2024-02-27", (&Data{Cubes: []DataCube{{Date: "2024-02-27"}, {Date: "2024-02-26"}}}).Time())
 }
@@ -0,0 +1,94 @@
+package xml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// DecodeCubes decodes XML document from r and calls fn for every cube containing rates of some date
+// in the order of their appearance in the document. Unlike NewData, it holds only a single cube in memory at a time.
+//
+// Decoding stops as soon as fn returns an error, which is then returned by DecodeCubes.
+// Like NewData, it rejects documents which are not the ECB reference rates envelopes.
+// If the document does not contain any XML element, io.EOF is returned, consistent with NewData.
+func DecodeCubes(r io.Reader, fn func(cube DataCube) error) error {
+	decoder := xml.NewDecoder(r)
+
+	var (
+		seenElement = false
+		seenSubject = false
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if !seenElement {
+				return io.EOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !seenElement {
+			seenElement = true
+			if err := checkRoot(start.Name, attrValue(start, "xmlns")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if start.Name.Local == "subject" && start.Name.Space == GesmesNamespace {
+			var subject string
+			if err := decoder.DecodeElement(&subject, &start); err != nil {
+				return err
+			}
+			if err := checkSubject(subject); err != nil {
+				return err
+			}
+			seenSubject = true
+			continue
+		}
+
+		if start.Name.Local != "Cube" || !hasAttr(start, "time") {
+			continue
+		}
+		if !seenSubject {
+			if err := checkSubject(""); err != nil {
+				return err
+			}
+		}
+
+		var cube DataCube
+		if err := decoder.DecodeElement(&cube, &start); err != nil {
+			return err
+		}
+		if err := fn(cube); err != nil {
+			return err
+		}
+	}
+}
+
+// attrValue returns value of the element attribute with the given local name and no namespace.
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether element has an attribute with the given local name.
+func hasAttr(element xml.StartElement, name string) bool {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package xml
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testHistXMLFilePath = "./../../testdata/eurofxref-hist.xml"
+
+func TestDecodeCubes(t *testing.T) {
+	t.Run("same cubes as NewData", func(t *testing.T) {
+		data, err := os.ReadFile(testHistXMLFilePath)
+		if err != nil {
+			panic(err)
+		}
+
+		expected, err := NewData(data)
+		if err != nil {
+			panic(err)
+		}
+
+		var cubes []DataCube
+		err = DecodeCubes(bytes.NewReader(data), func(cube DataCube) error {
+			cubes = append(cubes, cube)
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected.Cubes, cubes)
+		}
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		data, err := os.ReadFile(testHistXMLFilePath)
+		if err != nil {
+			panic(err)
+		}
+
+		var (
+			callbackErr = errors.New("callback error")
+			calls       = 0
+		)
+		err = DecodeCubes(bytes.NewReader(data), func(cube DataCube) error {
+			calls++
+			return callbackErr
+		})
+		assert.ErrorIs(t, err, callbackErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalid XML bytes", func(t *testing.T) {
+		err := DecodeCubes(strings.NewReader("invalid xml data"), func(cube DataCube) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("malformed XML", func(t *testing.T) {
+		err := DecodeCubes(strings.NewReader(`<gesmes:Envelope xmlns:gesmes="`+GesmesNamespace+`" xmlns="`+EurofxrefNamespace+`">`+
+			`<gesmes:subject>Reference rates</gesmes:subject><Cube><Cube time="2024-02-27"><Cube currency="USD"`), func(cube DataCube) error {
+			return nil
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unrelated XML", func(t *testing.T) {
+		err := DecodeCubes(strings.NewReader(`<Cube><Cube time="2024-02-27"><Cube currency="USD" rate="1"/></Cube></Cube>`), func(cube DataCube) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrUnexpectedRootElement)
+	})
+
+	t.Run("missing subject", func(t *testing.T) {
+		err := DecodeCubes(strings.NewReader(`<gesmes:Envelope xmlns:gesmes="`+GesmesNamespace+`" xmlns="`+EurofxrefNamespace+`">`+
+			`<Cube><Cube time="2024-02-27"><Cube currency="USD" rate="1"/></Cube></Cube></gesmes:Envelope>`), func(cube DataCube) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrUnexpectedSubject)
+	})
+}
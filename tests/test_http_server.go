@@ -1,11 +1,14 @@
 package tests
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
+	"sync"
 )
 
 const (
@@ -30,10 +33,55 @@ type TestHTTPServer struct {
 	fsPathTimeSeriesLast90Days string
 
 	isBroken bool
+
+	mu            sync.Mutex
+	requests      int
+	failures      int
+	failureStatus int
+	failureHeader http.Header
+}
+
+// FailNext makes the server respond to the next n requests with the given status code and headers.
+func (p *TestHTTPServer) FailNext(n int, statusCode int, header http.Header) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures = n
+	p.failureStatus = statusCode
+	p.failureHeader = header
+}
+
+// Requests returns number of requests handled by the server.
+func (p *TestHTTPServer) Requests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.requests
+}
+
+// nextFailure registers a new request and reports whether it should fail.
+func (p *TestHTTPServer) nextFailure() (int, http.Header, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests++
+	if p.failures == 0 {
+		return 0, nil, false
+	}
+	p.failures--
+	return p.failureStatus, p.failureHeader, true
 }
 
 func (p *TestHTTPServer) HandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if statusCode, header, fail := p.nextFailure(); fail {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+
 		if p.isBroken {
 			w.WriteHeader(http.StatusInternalServerError)
 			if _, err := w.Write([]byte("dude, I am broken!")); err != nil {
@@ -42,22 +90,20 @@ func (p *TestHTTPServer) HandlerFunc() http.HandlerFunc {
 			return
 		}
 
-		var (
-			data []byte
-			err  error
-		)
+		var fsPath string
 		switch r.URL.Path {
 		case p.urlPathLatest:
-			data, err = os.ReadFile(p.fsPathLatest)
+			fsPath = p.fsPathLatest
 		case p.urlPathTimeSeries:
-			data, err = os.ReadFile(p.fsPathTimeSeries)
+			fsPath = p.fsPathTimeSeries
 		case p.urlPathTimeSeriesLast90Days:
-			data, err = os.ReadFile(p.fsPathTimeSeriesLast90Days)
+			fsPath = p.fsPathTimeSeriesLast90Days
 		default:
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
 
+		data, err := os.ReadFile(fsPath)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			if _, err := w.Write([]byte(err.Error())); err != nil {
@@ -66,10 +112,18 @@ func (p *TestHTTPServer) HandlerFunc() http.HandlerFunc {
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write(data); err != nil {
-			panic(err)
+		info, err := os.Stat(fsPath)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			if _, err := w.Write([]byte(err.Error())); err != nil {
+				panic(err)
+			}
+			return
 		}
+
+		// serve content with validators, so that conditional requests are supported:
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(data)))
+		http.ServeContent(w, r, path.Base(fsPath), info.ModTime(), bytes.NewReader(data))
 	}
 }
 